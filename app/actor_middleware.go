@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v5"
+)
+
+type actorKey struct{}
+
+// WithActor records the authenticated principal making the request, the auth layer calls it once
+// the caller is verified.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored by WithActor, empty for anonymous requests.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// ActorMiddleware trusts the key header as the actor when no principal is on the request context
+// yet. Clients can set any header, so it is only for deployments where a gateway injects it, and an
+// empty key turns it off.
+func ActorMiddleware(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if key == "" {
+			return next
+		}
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+			if actor := req.Header.Get(key); actor != "" && ActorFrom(req.Context()) == "" {
+				ctx.SetRequest(req.WithContext(WithActor(req.Context(), actor)))
+			}
+			return next(ctx)
+		}
+	}
+}
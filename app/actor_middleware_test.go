@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/echotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	t.Run("should store actor from header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Actor", "admin@example.com")
+		ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+
+		handler := ActorMiddleware("X-Actor")(func(ctx *echo.Context) error {
+			assert.Equal(t, "admin@example.com", ActorFrom(ctx.Request().Context()))
+			return nil
+		})
+		assert.NoError(t, handler(ctx))
+	})
+
+	t.Run("should ignore the header when turned off", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Actor", "admin@example.com")
+		ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+
+		handler := ActorMiddleware("")(func(ctx *echo.Context) error {
+			assert.Empty(t, ActorFrom(ctx.Request().Context()))
+			return nil
+		})
+		assert.NoError(t, handler(ctx))
+	})
+
+	t.Run("should keep the authenticated principal", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Actor", "admin@example.com")
+		req = req.WithContext(WithActor(req.Context(), "alice"))
+		ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+
+		handler := ActorMiddleware("X-Actor")(func(ctx *echo.Context) error {
+			assert.Equal(t, "alice", ActorFrom(ctx.Request().Context()))
+			return nil
+		})
+		assert.NoError(t, handler(ctx))
+	})
+
+	t.Run("should leave anonymous requests empty", func(t *testing.T) {
+		ctx, _ := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/", nil),
+		}.ToContextRecorder(t)
+
+		handler := ActorMiddleware("X-Actor")(func(ctx *echo.Context) error {
+			assert.Empty(t, ActorFrom(ctx.Request().Context()))
+			return nil
+		})
+		assert.NoError(t, handler(ctx))
+	})
+}
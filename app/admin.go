@@ -0,0 +1,94 @@
+package app
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+	"github.com/kongsakchai/gotemplate/pkg/logger"
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+type logLevelBody struct {
+	Level string `json:"level" validate:"required"`
+}
+
+type routeInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+}
+
+// newAdmin builds the diagnostics server, it is only served by AdminHook on its own listener.
+func newAdmin(app *EchoApp) *echo.Echo {
+	e := echo.New()
+	e.Validator = app.Validator
+	e.HTTPErrorHandler = errorHandler
+	e.Use(middleware.Recover())
+
+	e.GET("/debug/pprof/", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	e.GET("/debug/pprof/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	e.GET("/debug/pprof/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	e.GET("/debug/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	e.POST("/debug/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	e.GET("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	e.GET("/debug/pprof/:name", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+
+	e.GET("/loglevel", getLogLevel)
+	e.PUT("/loglevel", setLogLevel)
+	e.GET("/config", func(ctx *echo.Context) error {
+		return Ok(ctx, config.Redacted(app.cfg))
+	})
+	e.GET("/buildinfo", VersionHandler)
+	e.GET("/routes", func(ctx *echo.Context) error {
+		return Ok(ctx, routesOf(app.Echo))
+	})
+
+	return e
+}
+
+// AdminHook serves the admin server on ADMIN_BIND:ADMIN_PORT.
+func (app *EchoApp) AdminHook(gracefulTimeout time.Duration) lifecycle.Hook {
+	return serveHook("admin", app.Admin, net.JoinHostPort(app.cfg.Admin.Bind, app.cfg.Admin.Port), gracefulTimeout)
+}
+
+func getLogLevel(ctx *echo.Context) error {
+	return Ok(ctx, logLevelBody{Level: logger.Level().String()})
+}
+
+func setLogLevel(ctx *echo.Context) error {
+	req := logLevelBody{}
+	if err := Request(ctx, &req); err != nil {
+		return err
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		return BadRequest(InValidCode, InValidMsg, err)
+	}
+
+	logger.UseLevel(level)
+	ctx.Logger().InfoContext(ctx.Request().Context(), "log level changed", "level", level.String())
+	return Ok(ctx, logLevelBody{Level: level.String()})
+}
+
+func routesOf(e *echo.Echo) []routeInfo {
+	routes := []routeInfo{}
+	for _, r := range e.Router().Routes() {
+		routes = append(routes, routeInfo{Method: r.Method, Path: r.Path, Name: r.Name})
+	}
+	slices.SortFunc(routes, func(a, b routeInfo) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	return routes
+}
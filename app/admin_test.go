@@ -0,0 +1,129 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/logger"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveAdmin(e *EchoApp, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.Admin.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdmin(t *testing.T) {
+	t.Run("should change log level at runtime", func(t *testing.T) {
+		defer logger.UseLevel(logger.Level())
+		e := NewEchoApp(config.Config{})
+
+		rec := serveAdmin(e, http.MethodPut, "/loglevel", `{"level":"debug"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"code":"0000","success":true,"data":{"level":"DEBUG"}}`, rec.Body.String())
+		assert.Equal(t, slog.LevelDebug, logger.Level())
+
+		rec = serveAdmin(e, http.MethodGet, "/loglevel", "")
+		assert.JSONEq(t, `{"code":"0000","success":true,"data":{"level":"DEBUG"}}`, rec.Body.String())
+	})
+
+	t.Run("should reject unknown log level", func(t *testing.T) {
+		defer logger.UseLevel(logger.Level())
+		logger.UseLevel(slog.LevelInfo)
+		e := NewEchoApp(config.Config{})
+
+		rec := serveAdmin(e, http.MethodPut, "/loglevel", `{"level":"verbose"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, slog.LevelInfo, logger.Level())
+	})
+
+	t.Run("should dump redacted config", func(t *testing.T) {
+		e := NewEchoApp(config.Config{
+			App:      config.App{Name: "gotemplate"},
+			Database: config.Database{URL: "root:secret@tcp(localhost:3306)/example"},
+		})
+
+		rec := serveAdmin(e, http.MethodGet, "/config", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"Name":"gotemplate"`)
+		assert.NotContains(t, rec.Body.String(), "secret")
+	})
+
+	t.Run("should list public routes", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		e.GET("/b", func(ctx *echo.Context) error { return nil })
+		e.POST("/a", func(ctx *echo.Context) error { return nil })
+
+		rec := serveAdmin(e, http.MethodGet, "/routes", "")
+
+		var resp struct {
+			Data []routeInfo `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, "/a", resp.Data[0].Path)
+		assert.Equal(t, http.MethodPost, resp.Data[0].Method)
+		assert.Equal(t, "/b", resp.Data[1].Path)
+	})
+
+	t.Run("should return build info", func(t *testing.T) {
+		rec := serveAdmin(NewEchoApp(config.Config{}), http.MethodGet, "/buildinfo", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"goVersion":"go`)
+	})
+
+	t.Run("should serve pprof", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+
+		rec := serveAdmin(e, http.MethodGet, "/debug/pprof/", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = serveAdmin(e, http.MethodGet, "/debug/pprof/goroutine?debug=1", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "goroutine profile")
+	})
+
+	t.Run("should not expose admin routes on the public app", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAdminHook(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+
+	e := NewEchoApp(config.Config{Admin: config.Admin{Bind: "127.0.0.1", Port: port}})
+	hook := e.AdminHook(time.Second)
+	assert.Equal(t, "admin", hook.Name)
+	require.NoError(t, hook.OnStart(t.Context()))
+
+	resp, err := http.Get("http://127.0.0.1:" + port + "/loglevel")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.NoError(t, hook.OnStop(t.Context()))
+	assert.True(t, e.Health.Ready(t.Context()).IsUp())
+}
@@ -9,6 +9,15 @@ type Response struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
 	Data    any    `json:"data,omitempty"`
+	Page    Page   `json:"page,omitzero"`
+}
+
+// Page describes a paginated Data, NextCursor is empty on the last page.
+type Page struct {
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
 }
 
 type RequestContext interface {
@@ -30,15 +39,42 @@ type Context interface {
 	JSON(code int, i any) (err error)
 }
 
+type codeSetter interface {
+	Set(key string, val any)
+}
+
+// setCode keeps the business code on the echo context so middleware can report it.
+func setCode(ctx Context, code string) {
+	if s, ok := ctx.(codeSetter); ok {
+		s.Set(CodeKey, code)
+	}
+}
+
 func Ok(ctx Context, data any, msg ...string) error {
 	message := ""
 	if len(msg) > 0 {
 		message = msg[0]
 	}
+	setCode(ctx, SuccessCode)
+	return ctx.JSON(http.StatusOK, Response{
+		Code:    SuccessCode,
+		Success: true,
+		Data:    data,
+		Message: message,
+	})
+}
+
+func OkPage(ctx Context, data any, page Page, msg ...string) error {
+	message := ""
+	if len(msg) > 0 {
+		message = msg[0]
+	}
+	setCode(ctx, SuccessCode)
 	return ctx.JSON(http.StatusOK, Response{
 		Code:    SuccessCode,
 		Success: true,
 		Data:    data,
+		Page:    page,
 		Message: message,
 	})
 }
@@ -48,6 +84,7 @@ func Created(ctx Context, data any, msg ...string) error {
 	if len(msg) > 0 {
 		message = msg[0]
 	}
+	setCode(ctx, SuccessCode)
 	return ctx.JSON(http.StatusCreated, Response{
 		Code:    SuccessCode,
 		Success: true,
@@ -57,6 +94,7 @@ func Created(ctx Context, data any, msg ...string) error {
 }
 
 func Fail(ctx Context, err Error) error {
+	setCode(ctx, err.Code)
 	return ctx.JSON(err.HTTPCode, Response{
 		Code:    err.Code,
 		Success: false,
@@ -110,6 +110,22 @@ func TestAppResponse(t *testing.T) {
 		assert.JSONEq(t, expectedResp, rec.Body.String())
 	})
 
+	t.Run("should return 200 OK with page when use OkPage", func(t *testing.T) {
+		// arrange
+		ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
+
+		total := int64(42)
+		expectedStatus := http.StatusOK
+		expectedResp := "{\"code\":\"0000\",\"success\":true,\"data\":[1,2],\"page\":{\"limit\":2,\"nextCursor\":\"abc\",\"total\":42}}\n"
+
+		// act
+		OkPage(ctx, []int{1, 2}, Page{Limit: 2, NextCursor: "abc", Total: &total})
+
+		// assert
+		assert.Equal(t, expectedStatus, rec.Code)
+		assert.JSONEq(t, expectedResp, rec.Body.String())
+	})
+
 	t.Run("should return 500 Internal Server Error when use FailWithError", func(t *testing.T) {
 		// arrange
 		ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
@@ -125,4 +141,12 @@ func TestAppResponse(t *testing.T) {
 		assert.Equal(t, expectedStatus, rec.Code)
 		assert.JSONEq(t, expectedResp, rec.Body.String())
 	})
+
+	t.Run("should keep business code on context", func(t *testing.T) {
+		ctx, _ := echotest.ContextConfig{}.ToContextRecorder(t)
+
+		Fail(ctx, Conflict(UsernameUnavailableCode, UsernameUnavailableMsg, nil))
+
+		assert.Equal(t, UsernameUnavailableCode, ctx.Get(CodeKey))
+	})
 }
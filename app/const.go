@@ -1,8 +1,12 @@
 package app
 
 const (
-	TraceIDKey = "traceID"
-	TagKey     = "tag"
+	// TraceIDKey holds the reference ID of the request, W3CTraceIDKey the W3C trace ID.
+	TraceIDKey    = "traceID"
+	W3CTraceIDKey = "w3cTraceID"
+	SpanIDKey     = "spanID"
+	TagKey        = "tag"
+	CodeKey       = "code"
 
 	// Common Code
 
@@ -14,17 +18,41 @@ const (
 	InValidCode    = "1001"
 	InValidMsg     = "invalid request"
 
-	DatabaseNotReadyCode = "9998"
-	DatabaseNotReadyMsg  = "database is not ready"
-	InternalErrorCode    = "9999"
-	InternalErrorMsg     = "internal error"
+	UnsupportedMediaTypeCode = "9992"
+	UnsupportedMediaTypeMsg  = "unsupported media type"
+	ServerBusyCode           = "9993"
+	ServerBusyMsg            = "server is busy, retry later"
+	PayloadTooLargeCode      = "9994"
+	PayloadTooLargeMsg       = "request body too large"
+	RequestTimeoutCode       = "9995"
+	RequestTimeoutMsg        = "request timeout"
+	ServiceNotReadyCode      = "9997"
+	ServiceNotReadyMsg       = "service is not ready"
+	DatabaseNotReadyCode     = "9998"
+	DatabaseNotReadyMsg      = "database is not ready"
+	InternalErrorCode        = "9999"
+	InternalErrorMsg         = "internal error"
 
 	// Business Code
 
-	InvalidAgeCode          = "1001"
-	InvalidAgeMsg           = "age invalid; age >= 15 and age <= 60"
-	UsernameUnavailableCode = "1002"
-	UsernameUnavailableMsg  = "username unavaliable"
-	MemberNotFoundCode      = "1003"
-	MemberNotFoundMsg       = "member not found"
+	InvalidAgeCode            = "1001"
+	InvalidAgeMsg             = "age out of the allowed range"
+	UsernameUnavailableCode   = "1002"
+	UsernameUnavailableMsg    = "username unavaliable"
+	MemberNotFoundCode        = "1003"
+	MemberNotFoundMsg         = "member not found"
+	InvalidMemberQueryCode    = "1004"
+	InvalidMemberQueryMsg     = "invalid member query"
+	InvalidMemberPatchCode    = "1005"
+	InvalidMemberPatchMsg     = "invalid member patch"
+	MemberVersionMismatchCode = "1006"
+	MemberVersionMismatchMsg  = "member was modified; reload and retry"
+	MemberNotDeletedCode      = "1007"
+	MemberNotDeletedMsg       = "member is not deleted"
+	InvalidMemberImportCode   = "1008"
+	InvalidMemberImportMsg    = "invalid member import"
+	InvalidImportRowCode      = "1009"
+	InvalidImportRowMsg       = "invalid import row"
+	MemberWriteConflictCode   = "1010"
+	MemberWriteConflictMsg    = "member was written concurrently; retry"
 )
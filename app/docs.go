@@ -34,3 +34,81 @@ type ErrorDatabaseResponse struct {
 		Message string `json:"message"`
 	}
 }
+
+// swagger:response errorServerBusyResponse
+type ErrorServerBusyResponse struct {
+	// in:body
+	Body struct {
+		// example: 9993
+		Code string `json:"code"`
+		// example: false
+		Success bool `json:"success"`
+		// example: server is busy, retry later
+		Message string `json:"message"`
+	}
+}
+
+// swagger:response errorPayloadTooLargeResponse
+type ErrorPayloadTooLargeResponse struct {
+	// in:body
+	Body struct {
+		// example: 9994
+		Code string `json:"code"`
+		// example: false
+		Success bool `json:"success"`
+		// example: request body too large
+		Message string `json:"message"`
+	}
+}
+
+// swagger:response errorRequestTimeoutResponse
+type ErrorRequestTimeoutResponse struct {
+	// in:body
+	Body struct {
+		// example: 9995
+		Code string `json:"code"`
+		// example: false
+		Success bool `json:"success"`
+		// example: request timeout
+		Message string `json:"message"`
+	}
+}
+
+// swagger:response errorServiceNotReadyResponse
+type ErrorServiceNotReadyResponse struct {
+	// in:body
+	Body struct {
+		// example: 9997
+		Code string `json:"code"`
+		// example: false
+		Success bool `json:"success"`
+		// example: service is not ready
+		Message string       `json:"message"`
+		Data    HealthReport `json:"data"`
+	}
+}
+
+type HealthReport struct {
+	// example: down
+	Status string `json:"status"`
+	Checks []struct {
+		// example: database
+		Name string `json:"name"`
+		// example: down
+		Status string `json:"status"`
+		// example: 1.2ms
+		Latency string `json:"latency"`
+		// example: connection refused
+		Error string `json:"error"`
+	} `json:"checks"`
+	// example: shutting down
+	Message string `json:"message"`
+	Build   struct {
+		// example: v1.2.3
+		Version string `json:"version"`
+		// example: 704b17e9c1f6b2a0d4e3f5a6b7c8d9e0f1a2b3c4
+		Revision string `json:"revision"`
+	} `json:"build"`
+	// example: 2026-01-01T00:00:00Z
+	CheckedAt string `json:"checkedAt"`
+}
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/kongsakchai/gotemplate/pkg/buildinfo"
 	"github.com/kongsakchai/gotemplate/pkg/config"
 	"github.com/kongsakchai/gotemplate/pkg/errs"
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
 	"github.com/kongsakchai/gotemplate/pkg/validator"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -17,6 +22,13 @@ import (
 
 type EchoApp struct {
 	*echo.Echo
+	Metrics *metrics.Registry
+	Health  *health.Health
+	// Admin serves diagnostics on a separate listener, see AdminHook.
+	Admin *echo.Echo
+
+	cfg     config.Config
+	modules []Module
 }
 
 func NewEchoApp(cfg config.Config) *EchoApp {
@@ -24,14 +36,36 @@ func NewEchoApp(cfg config.Config) *EchoApp {
 	e.Validator = validator.NewReqValidator()
 	e.HTTPErrorHandler = errorHandler
 
+	reg := metrics.NewRegistry()
+	reg.MustRegister(metrics.NewGoCollector())
+
 	e.Use(
+		MetricsMiddleware(reg),
 		middleware.Recover(),
-		middleware.CORS("*"),
-		RefIDMiddleware(cfg.Header.RefIDKey, cfg.Log.Tags),
-		LoggerMiddleware(cfg.Log.Enable),
 	)
+	if cfg.LoadShed.Enable {
+		e.Use(LoadShedMiddleware(cfg.LoadShed, reg))
+	}
+	e.Use(
+		CORSMiddleware(cfg.CORS),
+		SecurityHeadersMiddleware(cfg.Security),
+		TracingMiddleware(),
+		RefIDMiddleware(cfg.Header.RefIDKey, cfg.Log),
+		ActorMiddleware(cfg.Header.ActorKey),
+		LimitMiddleware(cfg.Limit),
+		LoggerMiddleware(cfg.Log),
+	)
+
+	hc := health.New(health.Config{
+		Timeout:  cfg.Health.Timeout,
+		CacheTTL: cfg.Health.CacheTTL,
+		Build:    buildinfo.Get(),
+	})
+
+	app := &EchoApp{Echo: e, Metrics: reg, Health: hc, cfg: cfg}
+	app.Admin = newAdmin(app)
 
-	return &EchoApp{Echo: e}
+	return app
 }
 
 func (app *EchoApp) Start(ctx context.Context, addr string, gracefulTimeout time.Duration) error {
@@ -39,6 +73,11 @@ func (app *EchoApp) Start(ctx context.Context, addr string, gracefulTimeout time
 		slog.DebugContext(ctx, r.Method, "path", r.Path)
 	}
 
+	// readiness turns false as soon as graceful shutdown begins
+	stop := context.AfterFunc(ctx, app.Health.Shutdown)
+	defer stop()
+	defer app.Health.Shutdown()
+
 	sc := echo.StartConfig{
 		Address:         addr,
 		GracefulTimeout: gracefulTimeout,
@@ -48,7 +87,88 @@ func (app *EchoApp) Start(ctx context.Context, addr string, gracefulTimeout time
 	return sc.Start(ctx, app)
 }
 
+// Hook serves the app on addr once its dependencies started. Stopping marks the app as not ready
+// before draining in-flight requests within gracefulTimeout.
+func (app *EchoApp) Hook(addr string, gracefulTimeout time.Duration, dependsOn ...string) lifecycle.Hook {
+	hook := serveHook("http", app.Echo, addr, gracefulTimeout)
+	hook.DependsOn = dependsOn
+
+	stop := hook.OnStop
+	hook.OnStop = func(ctx context.Context) error {
+		app.Health.Shutdown()
+		return stop(ctx)
+	}
+	return hook
+}
+
+func serveHook(name string, e *echo.Echo, addr string, gracefulTimeout time.Duration) lifecycle.Hook {
+	var (
+		cancel context.CancelFunc
+		done   = make(chan error, 1)
+	)
+
+	return lifecycle.Hook{
+		Name:    name,
+		Timeout: gracefulTimeout,
+		OnStart: func(ctx context.Context) error {
+			var serveCtx context.Context
+			serveCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+
+			listening := make(chan struct{})
+			sc := echo.StartConfig{
+				Address:          addr,
+				GracefulTimeout:  gracefulTimeout,
+				HidePort:         true,
+				HideBanner:       true,
+				ListenerAddrFunc: func(net.Addr) { close(listening) },
+			}
+			go func() {
+				err := sc.Start(serveCtx, e)
+				select {
+				case <-listening:
+					if err != nil && serveCtx.Err() == nil {
+						slog.Error(name + " server stopped unexpectedly: " + err.Error())
+					}
+				default:
+				}
+				done <- err
+			}()
+
+			select {
+			case <-listening:
+				return nil
+			case err := <-done:
+				cancel()
+				return err
+			case <-ctx.Done():
+				cancel()
+				return ctx.Err()
+			}
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
 func errorHandler(ctx *echo.Context, err error) {
+	if w, ok := ctx.Response().(*echoResponseWriter); ok {
+		defer w.logResponse()
+	}
+
+	// a streamed response can fail half way, the status is already sent
+	if resp, uerr := echo.UnwrapResponse(ctx.Response()); uerr == nil && resp.Committed {
+		ctx.Logger().LogAttrs(ctx.Request().Context(), slog.LevelError, "error after response committed", errs.SlogAttr(err)...)
+		return
+	}
+
 	if appErr, ok := err.(Error); ok {
 		ctx.Logger().LogAttrs(ctx.Request().Context(), slog.LevelError, "app error", errs.SlogAttr(appErr.Err)...)
 		if err := Fail(ctx, appErr); err != nil {
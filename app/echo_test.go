@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/echotest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type customMarshalerError struct {
@@ -39,6 +41,7 @@ func TestNewEchoApp(t *testing.T) {
 		assert.NotNil(t, e)
 		assert.NotNil(t, e.Validator)
 		assert.NotNil(t, e.HTTPErrorHandler)
+		assert.NotNil(t, e.Metrics)
 	})
 }
 
@@ -60,6 +63,59 @@ func TestStart(t *testing.T) {
 		err := e.Start(ctx, ":0", time.Second)
 		assert.NoError(t, err)
 	})
+
+	t.Run("should not be ready after shutdown begins", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		assert.True(t, e.Health.Ready(t.Context()).IsUp())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		assert.NoError(t, e.Start(ctx, ":0", time.Second))
+		assert.False(t, e.Health.Ready(t.Context()).IsUp())
+	})
+}
+
+func TestHook(t *testing.T) {
+	t.Run("should serve until stopped", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		e.GET("/", func(c *echo.Context) error {
+			return c.String(http.StatusOK, "ok")
+		})
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		hook := e.Hook(addr, time.Second, "database")
+		assert.Equal(t, "http", hook.Name)
+		assert.Equal(t, []string{"database"}, hook.DependsOn)
+		require.NoError(t, hook.OnStart(t.Context()))
+
+		resp, err := http.Get("http://" + addr + "/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.NoError(t, hook.OnStop(t.Context()))
+		assert.False(t, e.Health.Ready(t.Context()).IsUp())
+
+		_, err = http.Get("http://" + addr + "/")
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail to start when address is in use", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		hook := NewEchoApp(config.Config{}).Hook(ln.Addr().String(), time.Second)
+		assert.Error(t, hook.OnStart(t.Context()))
+	})
 }
 
 type failWriter struct {
@@ -151,6 +207,20 @@ func TestErrorHandler(t *testing.T) {
 	})
 }
 
+func TestErrorHandlerCommitted(t *testing.T) {
+	t.Run("should not write after response committed", func(t *testing.T) {
+		ctx, rec := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/test", nil),
+		}.ToContextRecorder(t)
+
+		require.NoError(t, ctx.String(http.StatusOK, "partial"))
+		errorHandler(ctx, errors.New("stream broken"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "partial", rec.Body.String())
+	})
+}
+
 func TestDefaultEchoErrorHandler(t *testing.T) {
 	t.Run("should handle nil error gracefully", func(t *testing.T) {
 		ctx, rec := echotest.ContextConfig{
@@ -21,6 +21,10 @@ func (e Error) Error() string {
 	return fmt.Sprintf("http_code=%d code=%s msg=\"%s\" data=%v err=%v", e.HTTPCode, e.Code, e.Message, e.Data, e.Err)
 }
 
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
 func errorData(data []any) any {
 	if len(data) == 0 {
 		return nil
@@ -90,3 +94,53 @@ func Conflict(code string, msg string, err error, data ...any) Error {
 		Data:     errorData(data),
 	}
 }
+
+func PreconditionFailed(code string, msg string, err error, data ...any) Error {
+	return Error{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     code,
+		Message:  msg,
+		Err:      err,
+		Data:     errorData(data),
+	}
+}
+
+func ServiceUnavailable(code string, msg string, err error, data ...any) Error {
+	return Error{
+		HTTPCode: http.StatusServiceUnavailable,
+		Code:     code,
+		Message:  msg,
+		Err:      err,
+		Data:     errorData(data),
+	}
+}
+
+func PayloadTooLarge(code string, msg string, err error, data ...any) Error {
+	return Error{
+		HTTPCode: http.StatusRequestEntityTooLarge,
+		Code:     code,
+		Message:  msg,
+		Err:      err,
+		Data:     errorData(data),
+	}
+}
+
+func GatewayTimeout(code string, msg string, err error, data ...any) Error {
+	return Error{
+		HTTPCode: http.StatusGatewayTimeout,
+		Code:     code,
+		Message:  msg,
+		Err:      err,
+		Data:     errorData(data),
+	}
+}
+
+func UnsupportedMediaType(code string, msg string, err error, data ...any) Error {
+	return Error{
+		HTTPCode: http.StatusUnsupportedMediaType,
+		Code:     code,
+		Message:  msg,
+		Err:      err,
+		Data:     errorData(data),
+	}
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
@@ -87,6 +88,52 @@ func TestNewError(t *testing.T) {
 		assert.Equal(t, expectedError, err)
 	})
 
+	t.Run("should return 503 Service Unavailable when use ServiceUnavailable", func(t *testing.T) {
+		expectedError := Error{
+			HTTPCode: http.StatusServiceUnavailable,
+			Code:     "5030",
+			Message:  "Service Unavailable",
+			Err:      nil,
+		}
+
+		err := ServiceUnavailable("5030", "Service Unavailable", nil)
+
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("should return 413 Request Entity Too Large when use PayloadTooLarge", func(t *testing.T) {
+		expectedError := Error{
+			HTTPCode: http.StatusRequestEntityTooLarge,
+			Code:     "4130",
+			Message:  "Payload Too Large",
+			Err:      nil,
+		}
+
+		err := PayloadTooLarge("4130", "Payload Too Large", nil)
+
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("should return 504 Gateway Timeout when use GatewayTimeout", func(t *testing.T) {
+		expectedError := Error{
+			HTTPCode: http.StatusGatewayTimeout,
+			Code:     "5040",
+			Message:  "Gateway Timeout",
+			Err:      nil,
+		}
+
+		err := GatewayTimeout("5040", "Gateway Timeout", nil)
+
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("should unwrap the cause", func(t *testing.T) {
+		cause := errors.New("cause")
+		err := InternalError("9999", "internal", cause)
+
+		assert.ErrorIs(t, err, cause)
+	})
+
 	t.Run("should return true when use IsEmpty", func(t *testing.T) {
 		err := Error{}
 		assert.True(t, err.IsEmpty())
@@ -119,4 +166,30 @@ func TestErrorData(t *testing.T) {
 		data := errorData([]any{"hello", "world"})
 		assert.Equal(t, []any{"hello", "world"}, data)
 	})
+
+	t.Run("should return 415 Unsupported Media Type when use UnsupportedMediaType", func(t *testing.T) {
+		expectedError := Error{
+			HTTPCode: http.StatusUnsupportedMediaType,
+			Code:     "4150",
+			Message:  "Unsupported Media Type",
+			Err:      nil,
+		}
+
+		err := UnsupportedMediaType("4150", "Unsupported Media Type", nil)
+
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("should return 412 Precondition Failed when use PreconditionFailed", func(t *testing.T) {
+		expectedError := Error{
+			HTTPCode: http.StatusPreconditionFailed,
+			Code:     "4120",
+			Message:  "Precondition Failed",
+			Err:      nil,
+		}
+
+		err := PreconditionFailed("4120", "Precondition Failed", nil)
+
+		assert.Equal(t, expectedError, err)
+	})
 }
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/labstack/echo/v5"
+)
+
+// LivenessHandler reports the process is alive, it never checks dependencies.
+func LivenessHandler(h *health.Health) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		return Ok(ctx, h.Live(), "alive")
+	}
+}
+
+// ReadinessHandler runs the registered checkers and fails with 503 when any is down or the app is shutting down.
+func ReadinessHandler(h *health.Health) echo.HandlerFunc {
+	return func(ctx *echo.Context) error {
+		report := h.Ready(ctx.Request().Context())
+		if !report.IsUp() {
+			return Fail(ctx, ServiceUnavailable(ServiceNotReadyCode, ServiceNotReadyMsg, nil, report))
+		}
+		return Ok(ctx, report, "healthy")
+	}
+}
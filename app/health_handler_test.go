@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/labstack/echo/v5/echotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	t.Run("should be alive even when a dependency is down", func(t *testing.T) {
+		h := health.New(health.Config{})
+		h.Register(health.NewChecker("db", func(context.Context) error { return errors.New("down") }))
+		ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
+
+		require.NoError(t, LivenessHandler(h)(ctx))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should be ready with check report", func(t *testing.T) {
+		h := health.New(health.Config{})
+		h.Register(health.NewChecker("db", func(context.Context) error { return nil }))
+		ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
+
+		require.NoError(t, ReadinessHandler(h)(ctx))
+
+		var resp struct {
+			Code string        `json:"code"`
+			Data health.Report `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, SuccessCode, resp.Code)
+		assert.Equal(t, health.StatusUp, resp.Data.Status)
+		assert.Equal(t, "db", resp.Data.Checks[0].Name)
+	})
+
+	t.Run("should fail with 503 when a dependency is down", func(t *testing.T) {
+		h := health.New(health.Config{})
+		h.Register(health.NewChecker("db", func(context.Context) error { return errors.New("down") }))
+		ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
+
+		require.NoError(t, ReadinessHandler(h)(ctx))
+
+		var resp struct {
+			Code string        `json:"code"`
+			Data health.Report `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, ServiceNotReadyCode, resp.Code)
+		assert.Equal(t, "down", resp.Data.Checks[0].Error)
+	})
+}
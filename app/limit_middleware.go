@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
+)
+
+// LimitMiddleware bounds the request context by a deadline and the body by a size, both resolved
+// per route group. It must run before anything reads the body, e.g. LoggerMiddleware.
+func LimitMiddleware(cfg config.Limit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			limit := limitOf(ctx, cfg)
+			req := ctx.Request()
+
+			if limit.BodySize > 0 {
+				if req.ContentLength > limit.BodySize {
+					return PayloadTooLarge(PayloadTooLargeCode, PayloadTooLargeMsg, nil)
+				}
+				req.Body = http.MaxBytesReader(ctx.Response(), req.Body, limit.BodySize)
+			}
+
+			if limit.Timeout > 0 {
+				ctx.Set(untimedContextKey, req.Context())
+				reqCtx, cancel := context.WithTimeout(req.Context(), limit.Timeout)
+				defer cancel()
+				req = req.WithContext(reqCtx)
+			}
+			ctx.SetRequest(req)
+
+			err := next(ctx)
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return PayloadTooLarge(PayloadTooLargeCode, PayloadTooLargeMsg, err)
+			}
+			if errors.Is(ctx.Request().Context().Err(), context.DeadlineExceeded) && !committed(ctx) {
+				return timeoutError(cfg.TimeoutStatus, err)
+			}
+			return err
+		}
+	}
+}
+
+const untimedContextKey = "limitUntimedContext"
+
+// WithoutTimeout lifts the LimitMiddleware deadline for the rest of the request, for downloads whose
+// length follows the data rather than the route. The request still ends when the client goes away.
+func WithoutTimeout(ctx *echo.Context) {
+	parent, ok := ctx.Get(untimedContextKey).(context.Context)
+	if !ok {
+		return
+	}
+	req := ctx.Request()
+	untimed, cancel := context.WithCancelCause(context.WithoutCancel(req.Context()))
+	// the server cancels parent once the request is done, which also releases untimed
+	context.AfterFunc(parent, func() { cancel(context.Cause(parent)) })
+	ctx.SetRequest(req.WithContext(untimed))
+}
+
+// limitOf merges the group with the longest prefix of the route into the defaults.
+func limitOf(ctx *echo.Context, cfg config.Limit) config.LimitGroup {
+	limit := config.LimitGroup{Timeout: cfg.Timeout, BodySize: cfg.BodySize}
+
+	route, matched := routeOf(ctx), ""
+	for prefix, group := range cfg.Groups {
+		if !strings.HasPrefix(route, prefix) || len(prefix) <= len(matched) {
+			continue
+		}
+		matched = prefix
+		if group.Timeout != 0 {
+			limit.Timeout = group.Timeout
+		} else {
+			limit.Timeout = cfg.Timeout
+		}
+		if group.BodySize != 0 {
+			limit.BodySize = group.BodySize
+		} else {
+			limit.BodySize = cfg.BodySize
+		}
+	}
+	return limit
+}
+
+func committed(ctx *echo.Context) bool {
+	resp, err := echo.UnwrapResponse(ctx.Response())
+	return err == nil && resp.Committed
+}
+
+func timeoutError(status int, err error) Error {
+	if status == http.StatusServiceUnavailable {
+		return ServiceUnavailable(RequestTimeoutCode, RequestTimeoutMsg, err)
+	}
+	return GatewayTimeout(RequestTimeoutCode, RequestTimeoutMsg, err)
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveLimit(t *testing.T, cfg config.Limit, req *http.Request, h echo.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.HTTPErrorHandler = errorHandler
+	e.Use(LimitMiddleware(cfg))
+	e.POST("/api/v1/members", h)
+	e.POST("/upload", h)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimitMiddleware(t *testing.T) {
+	readBody := func(ctx *echo.Context) error {
+		if _, err := io.ReadAll(ctx.Request().Body); err != nil {
+			return BadRequest(BadRequestCode, BadRequestMsg, err)
+		}
+		return Ok(ctx, nil)
+	}
+
+	t.Run("should reject body larger than content length limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", strings.NewReader(strings.Repeat("a", 11)))
+
+		rec := serveLimit(t, config.Limit{BodySize: 10}, req, readBody)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"`+PayloadTooLargeCode+`"`)
+	})
+
+	t.Run("should reject streamed body over the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", strings.NewReader(strings.Repeat("a", 11)))
+		req.ContentLength = -1
+
+		rec := serveLimit(t, config.Limit{BodySize: 10}, req, readBody)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("should use group body size", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("a", 11)))
+
+		rec := serveLimit(t, config.Limit{
+			BodySize: 10,
+			Groups:   config.LimitGroups{"/upload": {BodySize: 100}},
+		}, req, readBody)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should cancel request context after timeout", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", nil)
+
+		rec := serveLimit(t, config.Limit{
+			Timeout: time.Hour,
+			Groups:  config.LimitGroups{"/api/v1": {Timeout: time.Millisecond}},
+		}, req, func(ctx *echo.Context) error {
+			<-ctx.Request().Context().Done()
+			return InternalError(InternalErrorCode, InternalErrorMsg, ctx.Request().Context().Err())
+		})
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"`+RequestTimeoutCode+`"`)
+	})
+
+	t.Run("should answer 503 when configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", nil)
+
+		rec := serveLimit(t, config.Limit{Timeout: time.Millisecond, TimeoutStatus: http.StatusServiceUnavailable}, req,
+			func(ctx *echo.Context) error {
+				<-ctx.Request().Context().Done()
+				return ctx.Request().Context().Err()
+			})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("should keep response written before deadline", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", nil)
+
+		rec := serveLimit(t, config.Limit{Timeout: time.Millisecond}, req, func(ctx *echo.Context) error {
+			err := Ok(ctx, nil)
+			<-ctx.Request().Context().Done()
+			return err
+		})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should lift the deadline for the handler", func(t *testing.T) {
+		parent, cancel := context.WithCancel(t.Context())
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members", nil).WithContext(parent)
+
+		rec := serveLimit(t, config.Limit{Timeout: time.Millisecond}, req, func(ctx *echo.Context) error {
+			WithoutTimeout(ctx)
+			reqCtx := ctx.Request().Context()
+			_, ok := reqCtx.Deadline()
+			assert.False(t, ok)
+
+			time.Sleep(5 * time.Millisecond)
+			assert.NoError(t, reqCtx.Err())
+
+			cancel()
+			<-reqCtx.Done()
+			assert.ErrorIs(t, context.Cause(reqCtx), context.Canceled)
+			return Ok(ctx, nil)
+		})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should not change context without timeout", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/upload", nil)
+		serveLimit(t, config.Limit{}, req, func(ctx *echo.Context) error {
+			_, ok := ctx.Request().Context().Deadline()
+			require.False(t, ok)
+			require.NoError(t, context.Cause(ctx.Request().Context()))
+			return nil
+		})
+	})
+}
+
+func TestLimitOf(t *testing.T) {
+	cfg := config.Limit{
+		Timeout:  time.Second,
+		BodySize: 10,
+		Groups: config.LimitGroups{
+			"/api":            {Timeout: 2 * time.Second},
+			"/api/v1/members": {BodySize: 20},
+		},
+	}
+
+	t.Run("should return defaults when no group matched", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/health", "/health")
+		assert.Equal(t, config.LimitGroup{Timeout: time.Second, BodySize: 10}, limitOf(ctx, cfg))
+	})
+
+	t.Run("should use the longest prefix only", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/api/v1/members/alice", "/api/v1/members/:username")
+		assert.Equal(t, config.LimitGroup{Timeout: time.Second, BodySize: 20}, limitOf(ctx, cfg))
+	})
+
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/limiter"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/labstack/echo/v5"
+)
+
+const ignoreLatencyKey = "loadShedIgnoreLatency"
+
+// IgnoreLatency keeps the request out of the load shedding latency signal, streaming handlers call
+// it since their duration follows the payload rather than the load.
+func IgnoreLatency(ctx *echo.Context) {
+	ctx.Set(ignoreLatencyKey, true)
+}
+
+// LoadShedMiddleware rejects requests above an adaptive concurrency limit with 503 and Retry-After
+// instead of queueing them until they time out. Priority routes bypass the limit.
+func LoadShedMiddleware(cfg config.LoadShed, reg *metrics.Registry) echo.MiddlewareFunc {
+	l := limiter.NewAIMD(limiter.Config{
+		Initial:   cfg.InitialLimit,
+		Min:       cfg.MinLimit,
+		Max:       cfg.MaxLimit,
+		Threshold: cfg.Latency,
+		Backoff:   cfg.Backoff,
+	})
+
+	shed := metrics.NewCounterVec("http_requests_shed_total", "Requests rejected by the concurrency limit.", "method", "route")
+	reg.MustRegister(shed, metrics.NewCollectorFunc(func() []metrics.Family {
+		return []metrics.Family{{
+			Name:    "http_concurrency_limit",
+			Help:    "Current adaptive concurrency limit.",
+			Type:    metrics.TypeGauge,
+			Samples: []metrics.Sample{{Value: float64(l.Limit())}},
+		}}
+	}, "http_concurrency_limit"))
+
+	retryAfter := strconv.Itoa(max(int(cfg.RetryAfter.Round(time.Second).Seconds()), 1))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			route := routeOf(ctx)
+			if slices.Contains(cfg.PriorityRoutes, route) {
+				return next(ctx)
+			}
+
+			if !l.Acquire() {
+				if ctx.Path() == "" {
+					route = unmatchedRoute
+				}
+				shed.Inc(ctx.Request().Method, route)
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+				return ServiceUnavailable(ServerBusyCode, ServerBusyMsg, nil)
+			}
+
+			now := time.Now()
+			status := statusRecorder(ctx)
+			var err error
+			// deferred so a panicking handler still frees its slot
+			defer func() {
+				code := status(err)
+				dropped := code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+				threshold, measured := latencyOf(ctx, cfg)
+				if ignore, _ := ctx.Get(ignoreLatencyKey).(bool); ignore || !measured {
+					l.Skip(dropped)
+					return
+				}
+				l.ReleaseWithin(time.Since(now), threshold, dropped)
+			}()
+
+			err = next(ctx)
+			return err
+		}
+	}
+}
+
+// latencyOf is the latency target of the route prefix matching longest, a route overridden with 0
+// is not measured.
+func latencyOf(ctx *echo.Context, cfg config.LoadShed) (time.Duration, bool) {
+	threshold, route, matched := cfg.Latency, routeOf(ctx), ""
+	for prefix, latency := range cfg.LatencyRoutes {
+		if strings.HasPrefix(route, prefix) && len(prefix) > len(matched) {
+			threshold, matched = latency, prefix
+		}
+	}
+	return threshold, matched == "" || threshold > 0
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShedMiddleware(t *testing.T) {
+	started := make(chan struct{}, 1)
+	setup := func(cfg config.LoadShed) (*echo.Echo, *metrics.Registry, chan struct{}) {
+		reg := metrics.NewRegistry()
+		release := make(chan struct{})
+
+		e := echo.New()
+		e.HTTPErrorHandler = errorHandler
+		e.Use(LoadShedMiddleware(cfg, reg))
+		e.GET("/slow", func(ctx *echo.Context) error {
+			started <- struct{}{}
+			<-release
+			return Ok(ctx, nil)
+		})
+		e.GET("/livez", func(ctx *echo.Context) error { return Ok(ctx, nil) })
+		e.POST("/api/v1/members/import", func(ctx *echo.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return Ok(ctx, nil)
+		})
+		e.GET("/export", func(ctx *echo.Context) error {
+			IgnoreLatency(ctx)
+			time.Sleep(20 * time.Millisecond)
+			return Ok(ctx, nil)
+		})
+		e.GET("/busy", func(ctx *echo.Context) error {
+			return ServiceUnavailable(ServiceNotReadyCode, ServiceNotReadyMsg, nil)
+		})
+		return e, reg, release
+	}
+	serve := func(e *echo.Echo, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("should shed requests above the limit", func(t *testing.T) {
+		e, reg, release := setup(config.LoadShed{
+			InitialLimit:   1,
+			MinLimit:       1,
+			MaxLimit:       1,
+			RetryAfter:     2 * time.Second,
+			PriorityRoutes: []string{"/livez"},
+		})
+
+		done := make(chan struct{})
+		go func() {
+			serve(e, "/slow")
+			close(done)
+		}()
+		<-started
+
+		rec := serve(e, "/slow")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter))
+		assert.Contains(t, rec.Body.String(), `"code":"`+ServerBusyCode+`"`)
+
+		assert.Equal(t, http.StatusOK, serve(e, "/livez").Code, "priority route bypass the limit")
+
+		close(release)
+		<-done
+		assert.Equal(t, http.StatusOK, serve(e, "/livez").Code)
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.WriteText(&buf))
+		assert.Contains(t, buf.String(), "http_concurrency_limit 1\n")
+		assert.Contains(t, buf.String(), `http_requests_shed_total{method="GET",route="/slow"}`)
+	})
+
+	t.Run("should back off when handler is unavailable", func(t *testing.T) {
+		e, reg, _ := setup(config.LoadShed{InitialLimit: 10, MinLimit: 1, MaxLimit: 10, Backoff: 0.5})
+
+		assert.Equal(t, http.StatusServiceUnavailable, serve(e, "/busy").Code)
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.WriteText(&buf))
+		assert.Contains(t, buf.String(), "http_concurrency_limit 5\n")
+	})
+
+	t.Run("should not back off on a slow import", func(t *testing.T) {
+		e, reg, _ := setup(config.LoadShed{
+			InitialLimit:  10,
+			MinLimit:      1,
+			MaxLimit:      10,
+			Latency:       time.Millisecond,
+			Backoff:       0.5,
+			LatencyRoutes: config.LatencyRoutes{"/api/v1/members/import": 0},
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/members/import", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, http.StatusOK, serve(e, "/export").Code)
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.WriteText(&buf))
+		assert.Contains(t, buf.String(), "http_concurrency_limit 10\n")
+	})
+
+	t.Run("should back off on a slow request", func(t *testing.T) {
+		e, reg, _ := setup(config.LoadShed{InitialLimit: 10, MinLimit: 1, MaxLimit: 10, Latency: time.Millisecond, Backoff: 0.5})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/members/import", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.WriteText(&buf))
+		assert.Contains(t, buf.String(), "http_concurrency_limit 5\n")
+	})
+}
@@ -5,56 +5,134 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"strings"
 	"time"
 
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/logger"
 	"github.com/labstack/echo/v5"
 )
 
-func LoggerMiddleware(enable bool) echo.MiddlewareFunc {
+const (
+	defaultLogBodySize = 4 << 10
+	truncatedSuffix    = "...(truncated)"
+	omittedBody        = "[omitted]"
+)
+
+func LoggerMiddleware(cfg config.Log) echo.MiddlewareFunc {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultLogBodySize
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx *echo.Context) error {
 			traceID, _ := ctx.Get(TraceIDKey).(string)
+			w3cTraceID, _ := ctx.Get(W3CTraceIDKey).(string)
 			tag, _ := ctx.Get(TagKey).(string)
 			req := ctx.Request()
 
-			if !enable {
+			if !cfg.Enable {
+				return next(ctx)
+			}
+
+			policy := policyOf(ctx, cfg)
+			if policy.skip {
 				return next(ctx)
 			}
 
 			logger := ctx.Logger().With(
 				slog.String(TraceIDKey, traceID),
+				slog.String(W3CTraceIDKey, w3cTraceID),
 				slog.String(TagKey, tag),
 			)
 
-			responseWriter := ctx.Response()
-			ctx.SetResponse(&echoResponseWriter{
-				ResponseWriter: responseWriter,
+			responseWriter := &echoResponseWriter{
+				ResponseWriter: ctx.Response(),
 				logger:         logger,
 				ctx:            req.Context(),
 				url:            req.URL.String(),
 				now:            time.Now(),
-			})
-
-			b, err := io.ReadAll(req.Body)
-			if err != nil {
-				logger.ErrorContext(req.Context(), "failed to read request body",
-					"error", err,
-					TraceIDKey, traceID,
-					TagKey, tag,
-				)
-				return err
+				cfg:            cfg,
+				policy:         policy,
 			}
+			ctx.SetResponse(responseWriter)
 
-			logger.InfoContext(req.Context(), fmt.Sprintf("request %s", req.URL),
+			body := omittedBody
+			if policy.body && allowContentType(cfg.ContentTypes, req.Header.Get(echo.HeaderContentType)) {
+				b, err := peekBody(req.Body, cfg.MaxBodySize)
+				if err != nil {
+					logger.ErrorContext(req.Context(), "failed to read request body",
+						"error", err,
+						TraceIDKey, traceID,
+						TagKey, tag,
+					)
+					return err
+				}
+				body = logBody(b, cfg)
+				req.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(b), req.Body), Closer: req.Body}
+			}
+
+			logger.Log(req.Context(), policy.level, fmt.Sprintf("request %s", req.URL),
 				"method", req.Method,
-				"body", string(b),
+				"body", body,
 			)
 
-			req.Body.Close()
-			req.Body = io.NopCloser(bytes.NewBuffer(b))
 			ctx.SetLogger(logger)
 
-			return next(ctx)
+			// errors are written by errorHandler after the chain, which logs the response then
+			err := next(ctx)
+			if err == nil {
+				responseWriter.logResponse()
+			}
+			return err
+		}
+	}
+}
+
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// peekBody reads at most limit+1 bytes so callers can tell whether the body was truncated
+// without buffering the whole upload in memory.
+func peekBody(body io.Reader, limit int) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(body, int64(limit)+1))
+}
+
+func logBody(b []byte, cfg config.Log) string {
+	truncated := len(b) > cfg.MaxBodySize
+	if truncated {
+		b = b[:cfg.MaxBodySize]
+	}
+
+	masked := string(logger.MaskJSON(b, cfg.MaskFields))
+	if truncated {
+		return masked + truncatedSuffix
+	}
+	return masked
+}
+
+func allowContentType(allowed []string, contentType string) bool {
+	if len(allowed) == 0 || contentType == "" {
+		return true
+	}
+
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		media = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	for _, a := range allowed {
+		switch {
+		case a == "*/*" || a == media:
+			return true
+		case strings.HasPrefix(a, "*") && strings.HasSuffix(media, a[1:]):
+			return true
+		case strings.HasSuffix(a, "/*") && strings.HasPrefix(media, a[:len(a)-1]):
+			return true
 		}
 	}
+	return false
 }
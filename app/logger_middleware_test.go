@@ -1,15 +1,20 @@
 package app
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/kongsakchai/gotemplate/pkg/config"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/echotest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type errReader struct {
@@ -26,7 +31,7 @@ func TestLoggerMiddleware(t *testing.T) {
 			Request: httptest.NewRequest(http.MethodGet, "/test", nil),
 		}.ToContextRecorder(t)
 
-		middleware := LoggerMiddleware(false)
+		middleware := LoggerMiddleware(config.Log{Enable: false})
 		var called bool
 		handler := middleware(func(ctx *echo.Context) error {
 			called = true
@@ -43,7 +48,7 @@ func TestLoggerMiddleware(t *testing.T) {
 			Request: httptest.NewRequest(http.MethodGet, "/test", nil),
 		}.ToContextRecorder(t)
 
-		middleware := LoggerMiddleware(true)
+		middleware := LoggerMiddleware(config.Log{Enable: true})
 		var called bool
 		handler := middleware(func(ctx *echo.Context) error {
 			called = true
@@ -63,7 +68,7 @@ func TestLoggerMiddleware(t *testing.T) {
 			Request: req,
 		}.ToContextRecorder(t)
 
-		middleware := LoggerMiddleware(true)
+		middleware := LoggerMiddleware(config.Log{Enable: true})
 		var called bool
 		handler := middleware(func(ctx *echo.Context) error {
 			called = true
@@ -75,3 +80,141 @@ func TestLoggerMiddleware(t *testing.T) {
 		assert.False(t, called)
 	})
 }
+
+func newLogContext(t *testing.T, req *http.Request) (*echo.Context, *bytes.Buffer) {
+	ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+	buf := &bytes.Buffer{}
+	ctx.SetLogger(slog.New(slog.NewJSONHandler(buf, nil)))
+	return ctx, buf
+}
+
+func TestLoggerMiddlewareBody(t *testing.T) {
+	t.Run("should mask configured fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"username":"john","password":"secret"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		ctx, buf := newLogContext(t, req)
+
+		middleware := LoggerMiddleware(config.Log{Enable: true, MaskFields: []string{"password"}})
+		handler := middleware(func(ctx *echo.Context) error {
+			b, err := io.ReadAll(ctx.Request().Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"username":"john","password":"secret"}`, string(b))
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+		assert.Contains(t, buf.String(), `password\":\"***\"`)
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("should truncate body but pass full body to handler", func(t *testing.T) {
+		body := strings.Repeat("a", 20)
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		ctx, buf := newLogContext(t, req)
+
+		middleware := LoggerMiddleware(config.Log{Enable: true, MaxBodySize: 5})
+		handler := middleware(func(ctx *echo.Context) error {
+			b, err := io.ReadAll(ctx.Request().Body)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(b))
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+		assert.Contains(t, buf.String(), `"body":"aaaaa`+truncatedSuffix+`"`)
+	})
+
+	t.Run("should omit body when content type is not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("binary"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEOctetStream)
+		ctx, buf := newLogContext(t, req)
+
+		middleware := LoggerMiddleware(config.Log{Enable: true, ContentTypes: []string{echo.MIMEApplicationJSON}})
+		handler := middleware(func(ctx *echo.Context) error {
+			b, _ := io.ReadAll(ctx.Request().Body)
+			assert.Equal(t, "binary", string(b))
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+		assert.NotContains(t, buf.String(), `"body":"binary"`)
+		assert.Contains(t, buf.String(), omittedBody)
+	})
+
+	t.Run("should skip logging for opt-out path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		ctx, buf := newLogContext(t, req)
+
+		middleware := LoggerMiddleware(config.Log{Enable: true, SkipPaths: []string{"/health"}})
+		handler := middleware(func(ctx *echo.Context) error {
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestLoggerMiddlewareResponse(t *testing.T) {
+	serve := func(h echo.HandlerFunc) (*httptest.ResponseRecorder, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		e := echo.New()
+		e.Logger = slog.New(slog.NewJSONHandler(buf, nil))
+		e.HTTPErrorHandler = errorHandler
+		e.Use(LoggerMiddleware(config.Log{Enable: true}))
+		e.GET("/test", h)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+		return rec, buf
+	}
+
+	t.Run("should log one response for a streamed body", func(t *testing.T) {
+		rec, buf := serve(func(ctx *echo.Context) error {
+			ctx.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+			rc := http.NewResponseController(ctx.Response())
+			for range 3 {
+				ctx.Response().Write([]byte("data: ping\n\n"))
+				require.NoError(t, rc.Flush())
+			}
+			return nil
+		})
+
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, 1, strings.Count(buf.String(), `"msg":"response 200 /test"`))
+		assert.Contains(t, buf.String(), `"size":36`)
+	})
+
+	t.Run("should log the response written by the error handler", func(t *testing.T) {
+		rec, buf := serve(func(ctx *echo.Context) error {
+			return BadRequest(BadRequestCode, BadRequestMsg, nil)
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 1, strings.Count(buf.String(), `"msg":"response 400 /test"`))
+		assert.Contains(t, buf.String(), `\"code\":\"`+BadRequestCode+`\"`)
+	})
+}
+
+func TestAllowContentType(t *testing.T) {
+	testcases := []struct {
+		title       string
+		allowed     []string
+		contentType string
+		want        bool
+	}{
+		{"allow all when empty list", nil, "image/png", true},
+		{"allow when no content type", []string{"application/json"}, "", true},
+		{"allow exact match with params", []string{"application/json"}, "application/json; charset=UTF-8", true},
+		{"allow suffix wildcard", []string{"*+json"}, "application/merge-patch+json", true},
+		{"allow type wildcard", []string{"text/*"}, "text/csv", true},
+		{"deny other type", []string{"application/json"}, "multipart/form-data; boundary=x", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, allowContentType(tc.allowed, tc.contentType))
+		})
+	}
+}
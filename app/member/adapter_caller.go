@@ -0,0 +1,33 @@
+package member
+
+import (
+	"cmp"
+	"context"
+	"strings"
+
+	"github.com/kongsakchai/gotemplate/app"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+)
+
+// requestCaller reads the caller set on the request context by the app middlewares.
+type requestCaller struct{}
+
+func (requestCaller) Actor(ctx context.Context) string {
+	return cmp.Or(app.ActorFrom(ctx), "anonymous")
+}
+
+// maxTraceIDLen is the size of member_history.trace_id.
+const maxTraceIDLen = 255
+
+// TraceID is the reference ID clients see in the response header, or the W3C trace ID without one.
+func (requestCaller) TraceID(ctx context.Context) string {
+	refID, _ := ctx.Value(app.TraceIDKey).(string)
+	if refID == "" {
+		sc, _ := trace.FromContext(ctx)
+		return sc.TraceID
+	}
+	if len(refID) > maxTraceIDLen {
+		refID = strings.ToValidUTF8(refID[:maxTraceIDLen], "")
+	}
+	return refID
+}
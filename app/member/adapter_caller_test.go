@@ -0,0 +1,39 @@
+package member
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/app"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCaller(t *testing.T) {
+	t.Run("should read actor and trace id", func(t *testing.T) {
+		ctx := app.WithActor(context.Background(), "admin@example.com")
+		ctx = context.WithValue(ctx, app.TraceIDKey, "custom-ref-id")
+		ctx = trace.ContextWith(ctx, trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+
+		assert.Equal(t, "admin@example.com", requestCaller{}.Actor(ctx))
+		assert.Equal(t, "custom-ref-id", requestCaller{}.TraceID(ctx))
+	})
+
+	t.Run("should fall back to the w3c trace id", func(t *testing.T) {
+		ctx := trace.ContextWith(context.Background(), trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestCaller{}.TraceID(ctx))
+	})
+
+	t.Run("should cut a long reference id to the column size", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), app.TraceIDKey, strings.Repeat("a", maxTraceIDLen+10))
+
+		assert.Len(t, requestCaller{}.TraceID(ctx), maxTraceIDLen)
+	})
+
+	t.Run("should fall back to anonymous", func(t *testing.T) {
+		assert.Equal(t, "anonymous", requestCaller{}.Actor(context.Background()))
+		assert.Empty(t, requestCaller{}.TraceID(context.Background()))
+	})
+}
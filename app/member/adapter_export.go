@@ -0,0 +1,140 @@
+package member
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportFlushRows is how many rows are buffered before they are flushed to the client.
+const exportFlushRows = 100
+
+var exportColumns = []string{"username", "firstName", "lastName", "birthday", "registerDate", "deletedAt"}
+
+type memberEncoder interface {
+	Encode(m Member) error
+	Flush() error
+}
+
+func newMemberEncoder(w io.Writer, mediaType string) memberEncoder {
+	if mediaType == csvType {
+		return newCSVEncoder(w)
+	}
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+// csvEncoder writes the import columns plus registerDate and deletedAt, birthday as a date.
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(m Member) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.w.Write([]string{
+		csvSafe(m.Username),
+		csvSafe(m.FirstName),
+		csvSafe(m.LastName),
+		m.Birthday.Format(time.DateOnly),
+		m.RegisterDate.Format(time.RFC3339),
+		formatOptional(m.DeletedAt),
+	})
+}
+
+// Flush also writes the header of an empty export.
+func (e *csvEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write(exportColumns)
+}
+
+// csvSafe keeps spreadsheets from evaluating a value as a formula.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatOptional(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ndjsonEncoder writes each member in its JSON form on its own line.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(m Member) error {
+	return e.enc.Encode(m)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+// negotiate returns the offer the Accept header prefers, the first offer wins ties and is the
+// fallback when nothing matches.
+func negotiate(accept string, offers ...string) string {
+	best, bestQ := offers[0], 0.0
+	if strings.TrimSpace(accept) == "" {
+		return best
+	}
+	for _, offer := range offers {
+		if q := quality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// quality is the q value of the most specific Accept range matching offer, 0 when none does.
+func quality(accept, offer string) float64 {
+	q, specificity := 0.0, -1
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		s := -1
+		switch {
+		case mediaType == offer:
+			s = 2
+		case strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaType, "*")):
+			s = 1
+		case mediaType == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		if v, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			q = v
+		}
+	}
+	return q
+}
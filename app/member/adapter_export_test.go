@@ -0,0 +1,66 @@
+package member
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberEncoder(t *testing.T) {
+	member, now := newFixture()
+	member.RegisterDate = now
+
+	t.Run("should write csv with header", func(t *testing.T) {
+		deleted := member
+		deleted.Username = "=cmd"
+		deleted.DeletedAt = now.Add(time.Hour)
+
+		var buf bytes.Buffer
+		enc := newMemberEncoder(&buf, csvType)
+		require.NoError(t, enc.Encode(member))
+		require.NoError(t, enc.Encode(deleted))
+		require.NoError(t, enc.Flush())
+
+		assert.Equal(t, "username,firstName,lastName,birthday,registerDate,deletedAt\n"+
+			"john,John,Doe,2000-01-01,2025-01-01T00:00:00Z,\n"+
+			"'=cmd,John,Doe,2000-01-01,2025-01-01T00:00:00Z,2025-01-01T01:00:00Z\n", buf.String())
+	})
+
+	t.Run("should write csv header without rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newMemberEncoder(&buf, csvType).Flush())
+		assert.Equal(t, "username,firstName,lastName,birthday,registerDate,deletedAt\n", buf.String())
+	})
+
+	t.Run("should write one json member per line", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := newMemberEncoder(&buf, ndjsonType)
+		require.NoError(t, enc.Encode(member))
+		require.NoError(t, enc.Encode(member))
+		require.NoError(t, enc.Flush())
+
+		line := `{"username":"john","firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z","registerDate":"2025-01-01T00:00:00Z"}` + "\n"
+		assert.Equal(t, line+line, buf.String())
+	})
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", csvType, ndjsonType}
+	for accept, expected := range map[string]string{
+		"":                                     "application/json",
+		"*/*":                                  "application/json",
+		"text/csv":                             csvType,
+		"text/*":                               csvType,
+		"application/x-ndjson":                 ndjsonType,
+		"text/csv;q=0.5, application/x-ndjson": ndjsonType,
+		"text/csv, */*;q=0.1":                  csvType,
+		"text/csv;q=0, */*":                    "application/json",
+		"image/png":                            "application/json",
+		"text/html, application/json;q=0.9":    "application/json",
+	} {
+		assert.Equal(t, expected, negotiate(accept, offers...), accept)
+	}
+}
@@ -1,13 +1,30 @@
 package member
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kongsakchai/gotemplate/app"
+	"github.com/kongsakchai/gotemplate/pkg/jsonpatch"
 	"github.com/labstack/echo/v5"
 )
 
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
 type handler struct {
 	service Servicer
 }
@@ -21,16 +38,45 @@ func (h *handler) RegisterMemberHandler(app *app.EchoApp) {
 	api.GET("/", h.members)
 	api.GET("/:username", h.member)
 	api.POST("/", h.create)
+	api.POST("/import", h.importMembers)
 	api.PUT("/:username", h.update)
+	api.PATCH("/:username", h.patch)
 	api.DELETE("/:username", h.remove)
+	api.POST("/:username/restore", h.restore)
+	api.GET("/:username/history", h.history)
+
+	if app.Admin != nil {
+		app.Admin.DELETE("/members/:username", h.purge)
+	}
 }
 
 func (h *handler) handlerError(err error) error {
+	var appErr app.Error
 	switch {
+	case errors.As(err, &appErr):
+		return appErr
 	case errors.Is(err, ErrorMaxAge) || errors.Is(err, ErrorMinAge):
+		var ageErr *AgeError
+		if errors.As(err, &ageErr) {
+			return app.BadRequest(app.InvalidAgeCode, app.InvalidAgeMsg, err, ageLimits{MinAge: ageErr.MinYears, MaxAge: ageErr.MaxYears})
+		}
 		return app.BadRequest(app.InvalidAgeCode, app.InvalidAgeMsg, err)
 	case errors.Is(err, ErrorDuplicate):
 		return app.Conflict(app.UsernameUnavailableCode, app.UsernameUnavailableMsg, err)
+	case errors.Is(err, ErrorInvalidQuery):
+		return app.BadRequest(app.InvalidMemberQueryCode, app.InvalidMemberQueryMsg, err)
+	case errors.Is(err, ErrorInvalidImport):
+		return app.BadRequest(app.InvalidMemberImportCode, app.InvalidMemberImportMsg, err)
+	case errors.Is(err, ErrorInvalidRow):
+		return app.BadRequest(app.InvalidImportRowCode, app.InvalidImportRowMsg, err)
+	case errors.Is(err, ErrorInvalidPatch):
+		return app.BadRequest(app.InvalidMemberPatchCode, app.InvalidMemberPatchMsg, err)
+	case errors.Is(err, ErrorNotDeleted):
+		return app.Conflict(app.MemberNotDeletedCode, app.MemberNotDeletedMsg, err)
+	case errors.Is(err, ErrorVersionMismatch):
+		return app.PreconditionFailed(app.MemberVersionMismatchCode, app.MemberVersionMismatchMsg, err)
+	case errors.Is(err, ErrorConflict):
+		return app.Conflict(app.MemberWriteConflictCode, app.MemberWriteConflictMsg, err)
 	case errors.Is(err, ErrorMemberNotFound):
 		return app.NotFound(app.MemberNotFoundCode, app.MemberNotFoundMsg, err)
 	default:
@@ -38,12 +84,124 @@ func (h *handler) handlerError(err error) error {
 	}
 }
 
+// ageLimits tells the client the allowed range when an age is rejected.
+type ageLimits struct {
+	MinAge int `json:"minAge"`
+	MaxAge int `json:"maxAge,omitempty"`
+}
+
+type membersQuery struct {
+	Limit  int    `query:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `query:"cursor" json:"cursor"`
+	Page   int    `query:"page" json:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Sort   string `query:"sort" json:"sort" validate:"omitempty,oneof=username firstName lastName birthday registerDate"`
+	Order  string `query:"order" json:"order" validate:"omitempty,oneof=asc desc"`
+	Total  bool   `query:"total" json:"total"`
+
+	IncludeDeleted bool `query:"includeDeleted" json:"includeDeleted"`
+
+	Name           string    `query:"name" json:"name"`
+	BirthdayFrom   time.Time `query:"birthdayFrom" json:"birthdayFrom" format:"2006-01-02"`
+	BirthdayTo     time.Time `query:"birthdayTo" json:"birthdayTo" format:"2006-01-02"`
+	RegisteredFrom time.Time `query:"registeredFrom" json:"registeredFrom" format:"2006-01-02"`
+	RegisteredTo   time.Time `query:"registeredTo" json:"registeredTo" format:"2006-01-02"`
+}
+
+func (q membersQuery) toQuery() MemberQuery {
+	return MemberQuery{
+		Limit:          q.Limit,
+		Cursor:         q.Cursor,
+		Page:           q.Page,
+		Sort:           SortField(q.Sort),
+		Desc:           q.Order == "desc",
+		NamePrefix:     q.Name,
+		BirthdayFrom:   q.BirthdayFrom,
+		BirthdayTo:     q.BirthdayTo,
+		RegisteredFrom: q.RegisteredFrom,
+		RegisteredTo:   q.RegisteredTo,
+		WithTotal:      q.Total,
+		IncludeDeleted: q.IncludeDeleted,
+	}
+}
+
 func (h *handler) members(ctx *echo.Context) error {
-	members, err := h.service.Members(ctx.Request().Context())
+	req := membersQuery{}
+	if err := app.Request(ctx, &req); err != nil {
+		return err
+	}
+
+	query := req.toQuery()
+	ctx.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	if mediaType := negotiate(ctx.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON, csvType, ndjsonType); mediaType != echo.MIMEApplicationJSON {
+		return h.export(ctx, query, mediaType)
+	}
+
+	page, err := h.service.Members(ctx.Request().Context(), query)
 	if err != nil {
 		return h.handlerError(err)
 	}
-	return app.Ok(ctx, members)
+	return app.OkPage(ctx, page.Members, app.Page{
+		Limit:      cmp.Or(query.Limit, DefaultLimit),
+		Page:       query.Page,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
+
+// export streams every matching member as a download, rows are written as the storage reads them.
+func (h *handler) export(ctx *echo.Context, query MemberQuery, mediaType string) error {
+	app.IgnoreLatency(ctx)
+	app.WithoutTimeout(ctx)
+	members, err := h.service.Export(ctx.Request().Context(), query)
+	if err != nil {
+		return h.handlerError(err)
+	}
+
+	// the first row is read before the headers so a failing query still answers an error
+	next, stop := iter.Pull2(members)
+	defer stop()
+	m, err, ok := next()
+	if err != nil {
+		return h.handlerError(err)
+	}
+
+	ext := ".csv"
+	if mediaType == ndjsonType {
+		ext = ".ndjson"
+	}
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentType, mediaType+"; charset=utf-8")
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": "members" + ext}))
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	if err := writeMembers(ctx.Response(), mediaType, m, ok, next); err != nil {
+		// the 200 is already sent, so only a broken connection tells the client the file is cut short
+		ctx.Logger().ErrorContext(ctx.Request().Context(), "member export aborted", "err", err.Error())
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+// writeMembers encodes m and the rest of next, flushing every exportFlushRows rows.
+func writeMembers(w http.ResponseWriter, mediaType string, m Member, ok bool, next func() (Member, error, bool)) error {
+	var err error
+	rc := http.NewResponseController(w)
+	enc := newMemberEncoder(w, mediaType)
+	for n := 1; ok; n++ {
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+		if n%exportFlushRows == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			_ = rc.Flush()
+		}
+		if m, err, ok = next(); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
 }
 
 type usernameParam struct {
@@ -60,6 +218,12 @@ func (h *handler) member(ctx *echo.Context) error {
 	if err != nil {
 		return h.handlerError(err)
 	}
+
+	tag := etag(member.Version)
+	ctx.Response().Header().Set(headerETag, tag)
+	if noneMatch(ctx.Request().Header.Get(headerIfNoneMatch), tag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
 	return app.Ok(ctx, member)
 }
 
@@ -69,12 +233,140 @@ func (h *handler) remove(ctx *echo.Context) error {
 		return app.BadRequest(app.BadRequestCode, app.BadRequestMsg, err)
 	}
 
-	if err := h.service.Remove(ctx.Request().Context(), req.Username); err != nil {
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return h.handlerError(err)
+	}
+	if err := h.service.Remove(ctx.Request().Context(), req.Username, version); err != nil {
+		return h.handlerError(err)
+	}
+	return app.Ok(ctx, nil)
+}
+
+func (h *handler) restore(ctx *echo.Context) error {
+	req := usernameParam{}
+	if err := ctx.Bind(&req); err != nil {
+		return app.BadRequest(app.BadRequestCode, app.BadRequestMsg, err)
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return h.handlerError(err)
+	}
+	member, err := h.service.Restore(ctx.Request().Context(), req.Username, version)
+	if err != nil {
+		return h.handlerError(err)
+	}
+	ctx.Response().Header().Set(headerETag, etag(member.Version))
+	return app.Ok(ctx, member)
+}
+
+// purge is served on the admin server only.
+func (h *handler) purge(ctx *echo.Context) error {
+	req := usernameParam{}
+	if err := ctx.Bind(&req); err != nil {
+		return app.BadRequest(app.BadRequestCode, app.BadRequestMsg, err)
+	}
+
+	if err := h.service.Purge(ctx.Request().Context(), req.Username); err != nil {
 		return h.handlerError(err)
 	}
 	return app.Ok(ctx, nil)
 }
 
+type historyQuery struct {
+	Username string `param:"username" validate:"required"`
+	Limit    int    `query:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor   string `query:"cursor" json:"cursor"`
+}
+
+func (h *handler) history(ctx *echo.Context) error {
+	req := historyQuery{}
+	if err := app.Request(ctx, &req); err != nil {
+		return err
+	}
+
+	page, err := h.service.History(ctx.Request().Context(), req.Username, HistoryQuery{Limit: req.Limit, Cursor: req.Cursor})
+	if err != nil {
+		return h.handlerError(err)
+	}
+	return app.OkPage(ctx, page.Changes, app.Page{
+		Limit:      cmp.Or(req.Limit, DefaultLimit),
+		NextCursor: page.NextCursor,
+	})
+}
+
+type importQuery struct {
+	Mode string `query:"mode" json:"mode" validate:"omitempty,oneof=atomic best-effort dry-run"`
+}
+
+type importResultBody struct {
+	Line     int    `json:"line"`
+	Username string `json:"username,omitempty"`
+	Accepted bool   `json:"accepted"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type importReportBody struct {
+	Mode     ImportMode         `json:"mode"`
+	Accepted int                `json:"accepted"`
+	Rejected int                `json:"rejected"`
+	Imported int                `json:"imported"`
+	Rows     []importResultBody `json:"rows"`
+}
+
+// importMembers streams a CSV or NDJSON upload into the service, rejected rows carry the code
+// the same error would answer on a single create.
+func (h *handler) importMembers(ctx *echo.Context) error {
+	app.IgnoreLatency(ctx)
+	// the body is the upload, so only the query is bound
+	req := importQuery{}
+	if err := echo.BindQueryParams(ctx, &req); err != nil {
+		return app.BadRequest(app.BadRequestCode, app.BadRequestMsg, err)
+	}
+	if err := ctx.Validate(&req); err != nil {
+		return app.BadRequest(app.InValidCode, app.InValidMsg, err, err)
+	}
+
+	body := ctx.Request().Body
+	var rows iter.Seq2[ImportRow, error]
+	switch mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType)); mediaType {
+	case csvType:
+		rows = csvRows(body, ctx.Validate)
+	case ndjsonType:
+		rows = ndjsonRows(body, ctx.Validate)
+	default:
+		return app.UnsupportedMediaType(app.UnsupportedMediaTypeCode, app.UnsupportedMediaTypeMsg,
+			fmt.Errorf("content type %q", mediaType))
+	}
+
+	report, err := h.service.Import(ctx.Request().Context(), rows, ImportMode(cmp.Or(req.Mode, string(ImportAtomic))))
+	if err != nil {
+		return h.handlerError(err)
+	}
+
+	res := importReportBody{
+		Mode:     report.Mode,
+		Accepted: report.Accepted,
+		Rejected: report.Rejected,
+		Imported: report.Imported,
+		Rows:     make([]importResultBody, 0, len(report.Results)),
+	}
+	for _, r := range report.Results {
+		row := importResultBody{Line: r.Line, Username: r.Username, Accepted: r.Err == nil}
+		var appErr app.Error
+		if r.Err != nil && errors.As(h.handlerError(r.Err), &appErr) {
+			row.Code, row.Message = appErr.Code, appErr.Message
+			if errors.Is(r.Err, ErrorInvalidRow) {
+				row.Message = r.Err.Error()
+			}
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return app.Ok(ctx, res)
+}
+
 type createBody struct {
 	Username     string    `json:"username" validate:"required"`
 	FirstName    string    `json:"firstName" validate:"required"`
@@ -83,12 +375,22 @@ type createBody struct {
 	RegisterDate time.Time `json:"registerDate"`
 }
 
+func (b createBody) toMember() Member {
+	return Member{
+		Username:     b.Username,
+		FirstName:    b.FirstName,
+		LastName:     b.LastName,
+		Birthday:     b.Birthday,
+		RegisterDate: b.RegisterDate,
+	}
+}
+
 func (h *handler) create(ctx *echo.Context) error {
 	req := createBody{}
 	if err := app.Request(ctx, &req); err != nil {
 		return err
 	}
-	if err := h.service.Create(ctx.Request().Context(), Member(req)); err != nil {
+	if err := h.service.Create(ctx.Request().Context(), req.toMember()); err != nil {
 		return h.handlerError(err)
 	}
 	return app.Created(ctx, nil)
@@ -108,8 +410,105 @@ func (h *handler) update(ctx *echo.Context) error {
 	if err := app.Request(ctx, &req); err != nil {
 		return err
 	}
-	if err := h.service.Update(ctx.Request().Context(), req.Username, Member(req)); err != nil {
+	version, err := ifMatch(ctx)
+	if err != nil {
 		return h.handlerError(err)
 	}
-	return app.Created(ctx, nil)
+
+	member, err := h.service.Update(ctx.Request().Context(), req.Username, version, createBody(req).toMember())
+	if err != nil {
+		return h.handlerError(err)
+	}
+	ctx.Response().Header().Set(headerETag, etag(member.Version))
+	return app.Ok(ctx, member)
+}
+
+// patch accepts a merge patch (RFC 7396) or a JSON patch (RFC 6902) depending on Content-Type.
+func (h *handler) patch(ctx *echo.Context) error {
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != jsonpatch.MergePatchType && mediaType != jsonpatch.JSONPatchType {
+		return app.UnsupportedMediaType(app.UnsupportedMediaTypeCode, app.UnsupportedMediaTypeMsg,
+			fmt.Errorf("content type %q", mediaType))
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return h.handlerError(err)
+	}
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return app.BadRequest(app.BadRequestCode, app.BadRequestMsg, err)
+	}
+
+	apply := func(doc []byte) ([]byte, error) { return jsonpatch.MergePatch(doc, body) }
+	if mediaType == jsonpatch.JSONPatchType {
+		p, err := jsonpatch.Decode(body)
+		if err != nil {
+			return app.BadRequest(app.InvalidMemberPatchCode, app.InvalidMemberPatchMsg, err)
+		}
+		apply = p.Apply
+	}
+
+	member, err := h.service.Patch(ctx.Request().Context(), ctx.Param("username"), version, func(m Member) (Member, error) {
+		doc, err := json.Marshal(m)
+		if err != nil {
+			return Member{}, err
+		}
+		if doc, err = apply(doc); err != nil {
+			return Member{}, fmt.Errorf("%w: %w", ErrorInvalidPatch, err)
+		}
+
+		// the patched member is validated like a new one
+		req := createBody{}
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return Member{}, fmt.Errorf("%w: %w", ErrorInvalidPatch, err)
+		}
+		if err := ctx.Validate(&req); err != nil {
+			return Member{}, app.BadRequest(app.InValidCode, app.InValidMsg, fmt.Errorf("%w: %w", ErrorInvalidPatch, err), err)
+		}
+		return req.toMember(), nil
+	})
+	if err != nil {
+		return h.handlerError(err)
+	}
+	ctx.Response().Header().Set(headerETag, etag(member.Version))
+	return app.Ok(ctx, member)
+}
+
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch returns the version required by If-Match, 0 when the header is absent or "*".
+// Only a single strong ETag is supported, anything else can never match.
+func ifMatch(ctx *echo.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, opened := strings.CutPrefix(header, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	if !opened || !closed {
+		return 0, fmt.Errorf("%w: if-match %s", ErrorVersionMismatch, header)
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: if-match %s", ErrorVersionMismatch, header)
+	}
+	return version, nil
+}
+
+// noneMatch compares If-None-Match with the weak comparison RFC 9110 uses for GET.
+func noneMatch(header, tag string) bool {
+	for t := range strings.SplitSeq(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == tag {
+			return true
+		}
+	}
+	return false
 }
@@ -1,17 +1,25 @@
 package member
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"iter"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kongsakchai/gotemplate/app"
 	"github.com/kongsakchai/gotemplate/pkg/validator"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/echotest"
+	"github.com/labstack/echo/v5/middleware"
 	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewHandler(t *testing.T) {
@@ -30,7 +38,18 @@ func TestNewHandler(t *testing.T) {
 		h.RegisterMemberHandler(app)
 
 		routes := app.Router().Routes()
-		assert.Len(t, routes, 5)
+		assert.Len(t, routes, 9)
+	})
+
+	t.Run("should register purge on admin server", func(t *testing.T) {
+		h := NewHandler(newMockServicer(t))
+
+		app := &app.EchoApp{Echo: echo.New(), Admin: echo.New()}
+		h.RegisterMemberHandler(app)
+
+		routes := app.Admin.Router().Routes()
+		assert.Len(t, routes, 1)
+		assert.Equal(t, "/members/:username", routes[0].Path)
 	})
 }
 
@@ -53,6 +72,14 @@ func TestHandlerError(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, appErr.HTTPCode)
 	})
 
+	t.Run("should return the age limits", func(t *testing.T) {
+		err := h.handlerError(fmt.Errorf("create: %w", &AgeError{Err: ErrorMaxAge, Age: 61, MinYears: 15, MaxYears: 60}))
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidAgeCode, appErr.Code)
+		assert.Equal(t, ageLimits{MinAge: 15, MaxAge: 60}, appErr.Data)
+	})
+
 	t.Run("should return conflict for duplicate error", func(t *testing.T) {
 		err := h.handlerError(ErrorDuplicate)
 		appErr, ok := err.(app.Error)
@@ -61,6 +88,37 @@ func TestHandlerError(t *testing.T) {
 		assert.Equal(t, app.UsernameUnavailableCode, appErr.Code)
 	})
 
+	t.Run("should return bad request for invalid query error", func(t *testing.T) {
+		err := h.handlerError(ErrorInvalidQuery)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, appErr.HTTPCode)
+		assert.Equal(t, app.InvalidMemberQueryCode, appErr.Code)
+	})
+
+	t.Run("should return bad request for invalid patch error", func(t *testing.T) {
+		err := h.handlerError(ErrorInvalidPatch)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, appErr.HTTPCode)
+		assert.Equal(t, app.InvalidMemberPatchCode, appErr.Code)
+	})
+
+	t.Run("should keep app error", func(t *testing.T) {
+		err := h.handlerError(fmt.Errorf("wrapped: %w", app.BadRequest(app.InValidCode, app.InValidMsg, nil)))
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InValidCode, appErr.Code)
+	})
+
+	t.Run("should return conflict for not deleted error", func(t *testing.T) {
+		err := h.handlerError(ErrorNotDeleted)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, appErr.HTTPCode)
+		assert.Equal(t, app.MemberNotDeletedCode, appErr.Code)
+	})
+
 	t.Run("should return not found for member not found error", func(t *testing.T) {
 		err := h.handlerError(ErrorMemberNotFound)
 		appErr, ok := err.(app.Error)
@@ -79,30 +137,186 @@ func TestHandlerError(t *testing.T) {
 }
 
 func TestHandlerMembers(t *testing.T) {
+	v := validator.NewReqValidator()
+
 	t.Run("success", func(t *testing.T) {
 		member, _ := newFixture()
+		total := int64(1)
 
 		svc := newMockServicer(t)
-		svc.On("Members", contextBackground()).Return([]Member{member}, nil)
+		svc.On("Members", contextBackground(), MemberQuery{}).Return(MemberPage{Members: []Member{member}, NextCursor: "next", Total: &total}, nil)
 
 		h := NewHandler(svc)
 		ctx, rec := echotest.ContextConfig{
 			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members", nil),
 		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := h.members(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"page":{"limit":20,"nextCursor":"next","total":1}`)
+	})
+
+	t.Run("should bind query params", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.On("Members", contextBackground(), MemberQuery{
+			Limit:          5,
+			Page:           2,
+			Sort:           SortBirthday,
+			Desc:           true,
+			NamePrefix:     "jo",
+			BirthdayFrom:   time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			RegisteredTo:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			WithTotal:      true,
+			IncludeDeleted: true,
+		}).Return(MemberPage{Members: []Member{}}, nil)
+
+		h := NewHandler(svc)
+		ctx, rec := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet,
+				"/api/v1/members?limit=5&page=2&sort=birthday&order=desc&name=jo&birthdayFrom=2000-01-01&registeredTo=2025-12-31&total=true&includeDeleted=true", nil),
+		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
 
 		err := h.members(ctx)
 		assert.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `"data":[]`)
+		assert.Contains(t, rec.Body.String(), `"page":{"limit":5,"page":2}`)
+	})
+
+	t.Run("should stream csv download", func(t *testing.T) {
+		member, now := newFixture()
+		member.RegisterDate = now
+
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(contextBackground(), MemberQuery{NamePrefix: "jo"}).
+			Return(func(yield func(Member, error) bool) { yield(member, nil) }, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members?name=jo", nil)
+		req.Header.Set(echo.HeaderAccept, "text/csv")
+		ctx, rec := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := NewHandler(svc).members(ctx)
+		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `attachment; filename=members.csv`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+		assert.Equal(t, "username,firstName,lastName,birthday,registerDate,deletedAt\njohn,John,Doe,2000-01-01,2025-01-01T00:00:00Z,\n", rec.Body.String())
+	})
+
+	t.Run("should stream ndjson download", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(contextBackground(), MemberQuery{}).
+			Return(func(yield func(Member, error) bool) {
+				for range exportFlushRows + 1 {
+					if !yield(member, nil) {
+						return
+					}
+				}
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members", nil)
+		req.Header.Set(echo.HeaderAccept, "application/x-ndjson")
+		ctx, rec := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := NewHandler(svc).members(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, `attachment; filename=members.ndjson`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, exportFlushRows+1, strings.Count(rec.Body.String(), "\n"))
+	})
+
+	t.Run("should answer an error when the export fails before the first row", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(contextBackground(), MemberQuery{}).
+			Return(func(yield func(Member, error) bool) { yield(Member{}, errors.New("db err")) }, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members", nil)
+		req.Header.Set(echo.HeaderAccept, "text/csv")
+		ctx, rec := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := NewHandler(svc).members(ctx)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusInternalServerError, appErr.HTTPCode)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should abort the connection when the export fails mid-stream", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(mock.Anything, MemberQuery{}).
+			Return(func(yield func(Member, error) bool) {
+				for range exportFlushRows + 1 {
+					if !yield(member, nil) {
+						return
+					}
+				}
+				yield(Member{}, errors.New("db err"))
+			}, nil)
+
+		e := echo.New()
+		e.Validator = v
+		e.Use(middleware.Recover())
+		e.GET("/api/v1/members", NewHandler(svc).members)
+		srv := httptest.NewServer(e)
+		defer srv.Close()
+
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/v1/members", nil)
+		req.Header.Set(echo.HeaderAccept, "text/csv")
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		_, err = io.ReadAll(resp.Body)
+		assert.Error(t, err)
+	})
+
+	t.Run("should answer an export query error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(contextBackground(), MemberQuery{}).Return(nil, ErrorInvalidQuery)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members", nil)
+		req.Header.Set(echo.HeaderAccept, "application/x-ndjson")
+		ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := NewHandler(svc).members(ctx)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidMemberQueryCode, appErr.Code)
+	})
+
+	t.Run("should reject invalid query params", func(t *testing.T) {
+		h := NewHandler(newMockServicer(t))
+
+		for _, query := range []string{"limit=1000", "sort=password", "order=up", "page=2&cursor=abc", "birthdayFrom=yesterday"} {
+			ctx, _ := echotest.ContextConfig{
+				Request: httptest.NewRequest(http.MethodGet, "/api/v1/members?"+query, nil),
+			}.ToContextRecorder(t)
+			ctx.Echo().Validator = v
+
+			assert.Error(t, h.members(ctx), query)
+		}
 	})
 
 	t.Run("service error", func(t *testing.T) {
 		svc := newMockServicer(t)
-		svc.On("Members", contextBackground()).Return(nil, errors.New("service err"))
+		svc.On("Members", contextBackground(), MemberQuery{}).Return(MemberPage{}, errors.New("service err"))
 
 		h := NewHandler(svc)
 		ctx, _ := echotest.ContextConfig{
 			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members", nil),
 		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
 
 		err := h.members(ctx)
 		assert.Error(t, err)
@@ -129,6 +343,36 @@ func TestHandlerMember(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 
+	t.Run("should set etag and answer not modified", func(t *testing.T) {
+		member, _ := newFixture()
+		member.Version = 3
+
+		svc := newMockServicer(t)
+		svc.On("Member", contextBackground(), "john").Return(member, nil).Twice()
+
+		h := NewHandler(svc)
+		newContext := func(ifNoneMatch string) (*echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/members/john", nil)
+			req.Header.Set("If-None-Match", ifNoneMatch)
+			return echotest.ContextConfig{
+				Request: req,
+				PathValues: echo.PathValues{
+					{Name: "username", Value: "john"},
+				},
+			}.ToContextRecorder(t)
+		}
+
+		ctx, rec := newContext(`"2"`)
+		assert.NoError(t, h.member(ctx))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+		ctx, rec = newContext(`"1", W/"3"`)
+		assert.NoError(t, h.member(ctx))
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
 	t.Run("bind error - invalid body with json content type", func(t *testing.T) {
 		svc := newMockServicer(t)
 		h := NewHandler(svc)
@@ -220,7 +464,7 @@ func TestHandlerUpdate(t *testing.T) {
 		member, _ := newFixture()
 
 		svc := newMockServicer(t)
-		svc.On("Update", contextBackground(), "john", member).Return(nil)
+		svc.On("Update", contextBackground(), "john", int64(0), member).Return(member, nil)
 
 		h := NewHandler(svc)
 		body := `{"firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z"}`
@@ -235,7 +479,54 @@ func TestHandlerUpdate(t *testing.T) {
 
 		err := h.update(ctx)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should pass if-match version and return new etag", func(t *testing.T) {
+		member, _ := newFixture()
+		updated := member
+		updated.Version = 4
+
+		svc := newMockServicer(t)
+		svc.On("Update", contextBackground(), "john", int64(3), member).Return(updated, nil)
+
+		h := NewHandler(svc)
+		body := `{"firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/members/john", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"3"`)
+		ctx, rec := echotest.ContextConfig{
+			Request: req,
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := h.update(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("should reject weak if-match", func(t *testing.T) {
+		h := NewHandler(newMockServicer(t))
+		body := `{"firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/members/john", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `W/"3"`)
+		ctx, _ := echotest.ContextConfig{
+			Request: req,
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+
+		err := h.update(ctx)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusPreconditionFailed, appErr.HTTPCode)
+		assert.Equal(t, app.MemberVersionMismatchCode, appErr.Code)
 	})
 
 	t.Run("bind error - invalid json", func(t *testing.T) {
@@ -258,7 +549,7 @@ func TestHandlerUpdate(t *testing.T) {
 		member, _ := newFixture()
 
 		svc := newMockServicer(t)
-		svc.On("Update", contextBackground(), "john", member).Return(ErrorMemberNotFound)
+		svc.On("Update", contextBackground(), "john", int64(0), member).Return(Member{}, ErrorMemberNotFound)
 
 		h := NewHandler(svc)
 		body := `{"firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z"}`
@@ -276,10 +567,321 @@ func TestHandlerUpdate(t *testing.T) {
 	})
 }
 
+func TestHandlerPatch(t *testing.T) {
+	v := validator.NewReqValidator()
+	member, now := newFixture()
+	member.RegisterDate = now
+
+	newContext := func(t *testing.T, contentType, body string) (*echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/members/john", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		ctx, rec := echotest.ContextConfig{
+			Request: req,
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+		return ctx, rec
+	}
+
+	applyTo := func(m Member) func(context.Context, string, int64, PatchFunc) (Member, error) {
+		return func(_ context.Context, _ string, _ int64, patch PatchFunc) (Member, error) {
+			return patch(m)
+		}
+	}
+
+	t.Run("should apply merge patch", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).RunAndReturn(applyTo(member))
+
+		ctx, rec := newContext(t, "application/merge-patch+json", `{"firstName":"Johnny"}`)
+		err := NewHandler(svc).patch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"firstName":"Johnny"`)
+		assert.Contains(t, rec.Body.String(), `"lastName":"Doe"`)
+		assert.NotContains(t, rec.Body.String(), `"version"`)
+	})
+
+	t.Run("should apply json patch", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).RunAndReturn(applyTo(member))
+
+		body := `[{"op":"test","path":"/lastName","value":"Doe"},{"op":"replace","path":"/lastName","value":"Smith"}]`
+		ctx, rec := newContext(t, "application/json-patch+json", body)
+		err := NewHandler(svc).patch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"lastName":"Smith"`)
+	})
+
+	t.Run("should reject unsupported content type", func(t *testing.T) {
+		ctx, _ := newContext(t, "application/json", `{"firstName":"Johnny"}`)
+		err := NewHandler(newMockServicer(t)).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusUnsupportedMediaType, appErr.HTTPCode)
+	})
+
+	t.Run("should reject malformed json patch", func(t *testing.T) {
+		ctx, _ := newContext(t, "application/json-patch+json", `{"op":"add"}`)
+		err := NewHandler(newMockServicer(t)).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidMemberPatchCode, appErr.Code)
+	})
+
+	t.Run("should reject failed test operation", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).RunAndReturn(applyTo(member))
+
+		ctx, _ := newContext(t, "application/json-patch+json", `[{"op":"test","path":"/lastName","value":"Smith"}]`)
+		err := NewHandler(svc).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidMemberPatchCode, appErr.Code)
+	})
+
+	t.Run("should reject unknown field", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).RunAndReturn(applyTo(member))
+
+		ctx, _ := newContext(t, "application/merge-patch+json", `{"nickname":"JJ"}`)
+		err := NewHandler(svc).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidMemberPatchCode, appErr.Code)
+	})
+
+	t.Run("should validate patched member", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).RunAndReturn(applyTo(member))
+
+		ctx, _ := newContext(t, "application/merge-patch+json", `{"firstName":null}`)
+		err := NewHandler(svc).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InValidCode, appErr.Code)
+		assert.ErrorIs(t, err, ErrorInvalidPatch)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).Return(Member{}, ErrorMemberNotFound)
+
+		ctx, _ := newContext(t, "application/merge-patch+json", `{"firstName":"Johnny"}`)
+		err := NewHandler(svc).patch(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.MemberNotFoundCode, appErr.Code)
+	})
+}
+
+func TestHandlerRestore(t *testing.T) {
+	newContext := func(t *testing.T, ifMatch string) (*echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members/john/restore", nil)
+		req.Header.Set("If-Match", ifMatch)
+		return echotest.ContextConfig{
+			Request: req,
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		member, _ := newFixture()
+		member.Version = 4
+
+		svc := newMockServicer(t)
+		svc.EXPECT().Restore(contextBackground(), "john", int64(3)).Return(member, nil)
+
+		ctx, rec := newContext(t, `"3"`)
+		err := NewHandler(svc).restore(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Restore(contextBackground(), "john", int64(0)).Return(Member{}, ErrorMemberNotFound)
+
+		ctx, _ := newContext(t, "")
+		err := NewHandler(svc).restore(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestHandlerHistory(t *testing.T) {
+	v := validator.NewReqValidator()
+	newContext := func(t *testing.T, query string) (*echo.Context, *httptest.ResponseRecorder) {
+		ctx, rec := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members/john/history"+query, nil),
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+		return ctx, rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		change := Change{
+			Username:  "john",
+			Version:   2,
+			Action:    ActionUpdate,
+			Actor:     "admin",
+			Diff:      []FieldChange{{Field: FieldFirstName, Before: "John", After: "Johnny"}},
+			ChangedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		svc := newMockServicer(t)
+		svc.EXPECT().History(contextBackground(), "john", HistoryQuery{Limit: 5, Cursor: "Mg"}).
+			Return(HistoryPage{Changes: []Change{change}, NextCursor: "MQ"}, nil)
+
+		ctx, rec := newContext(t, "?limit=5&cursor=Mg")
+		err := NewHandler(svc).history(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"diff":[{"field":"firstName","before":"John","after":"Johnny"}]`)
+		assert.Contains(t, rec.Body.String(), `"page":{"limit":5,"nextCursor":"MQ"}`)
+	})
+
+	t.Run("should reject invalid limit", func(t *testing.T) {
+		ctx, _ := newContext(t, "?limit=1000")
+		assert.Error(t, NewHandler(newMockServicer(t)).history(ctx))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().History(contextBackground(), "john", HistoryQuery{}).Return(HistoryPage{}, ErrorMemberNotFound)
+
+		ctx, _ := newContext(t, "")
+		err := NewHandler(svc).history(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.MemberNotFoundCode, appErr.Code)
+	})
+}
+
+func TestHandlerImport(t *testing.T) {
+	v := validator.NewReqValidator()
+	newContext := func(t *testing.T, query, contentType, body string) (*echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/members/import"+query, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		ctx, rec := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+		ctx.Echo().Validator = v
+		return ctx, rec
+	}
+
+	t.Run("should report every row", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Import(contextBackground(), mock.Anything, ImportDryRun).
+			RunAndReturn(func(_ context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error) {
+				report := ImportReport{Mode: mode}
+				for row := range rows {
+					err := row.Err
+					if err == nil && row.Member.Username == "john" {
+						err = ErrorDuplicate
+					}
+					report.add(row.Line, row.Member.Username, err)
+				}
+				return report, nil
+			})
+
+		body := "username,firstName,lastName,birthday\njohn,John,Doe,2000-01-01\njane,,Roe,2000-01-01\nann,Ann,Lee,2000-01-01\n"
+		ctx, rec := newContext(t, "?mode=dry-run", "text/csv; charset=utf-8", body)
+		err := NewHandler(svc).importMembers(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"mode":"dry-run","accepted":1,"rejected":2,"imported":0`)
+		assert.Contains(t, rec.Body.String(), `{"line":2,"username":"john","accepted":false,"code":"1002","message":"username unavaliable"}`)
+		assert.Contains(t, rec.Body.String(), `{"line":3,"username":"jane","accepted":false,"code":"1009","message":"invalid import row: firstName: required"}`)
+		assert.Contains(t, rec.Body.String(), `{"line":4,"username":"ann","accepted":true}`)
+	})
+
+	t.Run("should default to atomic ndjson", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Import(contextBackground(), mock.Anything, ImportAtomic).Return(ImportReport{Mode: ImportAtomic}, nil)
+
+		ctx, rec := newContext(t, "", "application/x-ndjson", "")
+		assert.NoError(t, NewHandler(svc).importMembers(ctx))
+		assert.Contains(t, rec.Body.String(), `"rows":[]`)
+	})
+
+	t.Run("should reject unknown mode", func(t *testing.T) {
+		ctx, _ := newContext(t, "?mode=all", "text/csv", "")
+		assert.Error(t, NewHandler(newMockServicer(t)).importMembers(ctx))
+	})
+
+	t.Run("should reject unsupported content type", func(t *testing.T) {
+		ctx, _ := newContext(t, "", echo.MIMEApplicationJSON, "[]")
+		err := NewHandler(newMockServicer(t)).importMembers(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusUnsupportedMediaType, appErr.HTTPCode)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Import(contextBackground(), mock.Anything, ImportAtomic).Return(ImportReport{}, ErrorInvalidImport)
+
+		ctx, _ := newContext(t, "", "text/csv", "")
+		err := NewHandler(svc).importMembers(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.InvalidMemberImportCode, appErr.Code)
+	})
+}
+
+func TestHandlerPurge(t *testing.T) {
+	newContext := func(t *testing.T) (*echo.Context, *httptest.ResponseRecorder) {
+		return echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodDelete, "/members/john", nil),
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Purge(contextBackground(), "john").Return(nil)
+
+		ctx, rec := newContext(t)
+		err := NewHandler(svc).purge(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Purge(contextBackground(), "john").Return(ErrorNotDeleted)
+
+		ctx, _ := newContext(t)
+		err := NewHandler(svc).purge(ctx)
+
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, app.MemberNotDeletedCode, appErr.Code)
+	})
+}
+
 func TestHandlerRemove(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		svc := newMockServicer(t)
-		svc.On("Remove", contextBackground(), "john").Return(nil)
+		svc.On("Remove", contextBackground(), "john", int64(0)).Return(nil)
 
 		h := NewHandler(svc)
 		ctx, rec := echotest.ContextConfig{
@@ -294,6 +896,43 @@ func TestHandlerRemove(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 
+	t.Run("should reject stale if-match", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.On("Remove", contextBackground(), "john", int64(2)).Return(ErrorVersionMismatch)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/members/john", nil)
+		req.Header.Set("If-Match", `"2"`)
+		ctx, _ := echotest.ContextConfig{
+			Request: req,
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+
+		err := NewHandler(svc).remove(ctx)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusPreconditionFailed, appErr.HTTPCode)
+	})
+
+	t.Run("should answer conflict when a concurrent write wins", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.On("Remove", contextBackground(), "john", int64(0)).Return(fmt.Errorf("remove member: %w", ErrorConflict))
+
+		ctx, _ := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodDelete, "/api/v1/members/john", nil),
+			PathValues: echo.PathValues{
+				{Name: "username", Value: "john"},
+			},
+		}.ToContextRecorder(t)
+
+		err := NewHandler(svc).remove(ctx)
+		appErr, ok := err.(app.Error)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusConflict, appErr.HTTPCode)
+		assert.Equal(t, app.MemberWriteConflictCode, appErr.Code)
+	})
+
 	t.Run("bind error - invalid body with json content type", func(t *testing.T) {
 		svc := newMockServicer(t)
 		h := NewHandler(svc)
@@ -309,7 +948,7 @@ func TestHandlerRemove(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		svc := newMockServicer(t)
-		svc.On("Remove", contextBackground(), "john").Return(ErrorMemberNotFound)
+		svc.On("Remove", contextBackground(), "john", int64(0)).Return(ErrorMemberNotFound)
 
 		h := NewHandler(svc)
 		ctx, _ := echotest.ContextConfig{
@@ -323,3 +962,37 @@ func TestHandlerRemove(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestIfMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		header  string
+		version int64
+		err     bool
+	}{
+		{name: "absent", header: ""},
+		{name: "any", header: "*"},
+		{name: "strong etag", header: `"5"`, version: 5},
+		{name: "missing leading quote", header: `5"`, err: true},
+		{name: "missing trailing quote", header: `"5`, err: true},
+		{name: "unquoted", header: "5", err: true},
+		{name: "weak etag", header: `W/"5"`, err: true},
+		{name: "not a version", header: `"abc"`, err: true},
+		{name: "zero version", header: `"0"`, err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/members/john", nil)
+			req.Header.Set("If-Match", c.header)
+			ctx, _ := echotest.ContextConfig{Request: req}.ToContextRecorder(t)
+
+			version, err := ifMatch(ctx)
+			if c.err {
+				assert.ErrorIs(t, err, ErrorVersionMismatch)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.version, version)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package member
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	csvType    = "text/csv"
+	ndjsonType = "application/x-ndjson"
+)
+
+// maxNDJSONLine bounds one NDJSON line, a longer one aborts the import.
+const maxNDJSONLine = 64 << 10
+
+// csvColumns are the accepted CSV header names, they match the JSON fields of createBody.
+var csvColumns = []string{"username", "firstName", "lastName", "birthday"}
+
+// csvRows decodes a CSV upload with a header row, columns may come in any order.
+// Birthday is either a date (2006-01-02) or an RFC 3339 time.
+func csvRows(r io.Reader, validate func(any) error) iter.Seq2[ImportRow, error] {
+	return func(yield func(ImportRow, error) bool) {
+		cr := csv.NewReader(r)
+		cr.TrimLeadingSpace = true
+
+		header, err := cr.Read()
+		if err != nil {
+			yield(ImportRow{}, fmt.Errorf("%w: csv header: %w", ErrorInvalidImport, err))
+			return
+		}
+		index := map[string]int{}
+		for i, name := range header {
+			name = strings.TrimSpace(name)
+			if _, dup := index[name]; dup || !slices.Contains(csvColumns, name) {
+				yield(ImportRow{}, fmt.Errorf("%w: csv column %q", ErrorInvalidImport, name))
+				return
+			}
+			index[name] = i
+		}
+		for _, name := range csvColumns {
+			if _, ok := index[name]; !ok {
+				yield(ImportRow{}, fmt.Errorf("%w: missing csv column %q", ErrorInvalidImport, name))
+				return
+			}
+		}
+
+		for {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			row := ImportRow{}
+
+			var parseErr *csv.ParseError
+			switch {
+			case errors.As(err, &parseErr):
+				// no field was read, so the line only comes from the error
+				row.Line = parseErr.StartLine
+				row.Err = fmt.Errorf("%w: %w", ErrorInvalidRow, parseErr.Err)
+			case err != nil:
+				yield(ImportRow{}, err)
+				return
+			default:
+				row.Line, _ = cr.FieldPos(0)
+				row.Member, row.Err = csvMember(record, index, validate)
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func csvMember(record []string, index map[string]int, validate func(any) error) (Member, error) {
+	body := createBody{
+		Username:  strings.TrimSpace(record[index["username"]]),
+		FirstName: strings.TrimSpace(record[index["firstName"]]),
+		LastName:  strings.TrimSpace(record[index["lastName"]]),
+	}
+	if birthday := strings.TrimSpace(record[index["birthday"]]); birthday != "" {
+		t, err := time.Parse(time.DateOnly, birthday)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, birthday); err != nil {
+				return body.toMember(), fmt.Errorf("%w: birthday %q", ErrorInvalidRow, birthday)
+			}
+		}
+		body.Birthday = t
+	}
+	if err := validate(&body); err != nil {
+		return body.toMember(), fmt.Errorf("%w: %w", ErrorInvalidRow, err)
+	}
+	return body.toMember(), nil
+}
+
+// ndjsonRows decodes one member JSON object per line, blank lines are skipped.
+func ndjsonRows(r io.Reader, validate func(any) error) iter.Seq2[ImportRow, error] {
+	return func(yield func(ImportRow, error) bool) {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 4096), maxNDJSONLine)
+
+		for line := 1; sc.Scan(); line++ {
+			b := bytes.TrimSpace(sc.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+
+			row := ImportRow{Line: line}
+			row.Member, row.Err = ndjsonMember(b, validate)
+			if !yield(row, nil) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				err = fmt.Errorf("%w: line longer than %d bytes", ErrorInvalidImport, maxNDJSONLine)
+			}
+			yield(ImportRow{}, err)
+		}
+	}
+}
+
+func ndjsonMember(b []byte, validate func(any) error) (Member, error) {
+	body := createBody{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		return body.toMember(), fmt.Errorf("%w: %w", ErrorInvalidRow, err)
+	}
+	if dec.More() {
+		return body.toMember(), fmt.Errorf("%w: more than one value on the line", ErrorInvalidRow)
+	}
+	if err := validate(&body); err != nil {
+		return body.toMember(), fmt.Errorf("%w: %w", ErrorInvalidRow, err)
+	}
+	return body.toMember(), nil
+}
@@ -0,0 +1,143 @@
+package member
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectRows(t *testing.T, rows iter.Seq2[ImportRow, error]) ([]ImportRow, error) {
+	t.Helper()
+	var got []ImportRow
+	for row, err := range rows {
+		if err != nil {
+			return got, err
+		}
+		got = append(got, row)
+	}
+	return got, nil
+}
+
+func TestCSVRows(t *testing.T) {
+	validate := validator.NewReqValidator().Validate
+
+	t.Run("should decode rows by header", func(t *testing.T) {
+		body := "lastName,username,firstName,birthday\n" +
+			"Doe,john,John,2000-01-01\n" +
+			"Roe, jane ,Jane,2001-02-03T00:00:00Z\n"
+
+		rows, err := collectRows(t, csvRows(strings.NewReader(body), validate))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, ImportRow{Line: 2, Member: Member{
+			Username:  "john",
+			FirstName: "John",
+			LastName:  "Doe",
+			Birthday:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		}}, rows[0])
+		assert.Equal(t, "jane", rows[1].Member.Username)
+		assert.Equal(t, 3, rows[1].Line)
+	})
+
+	t.Run("should reject invalid rows", func(t *testing.T) {
+		body := "username,firstName,lastName,birthday\n" +
+			"john,,Doe,2000-01-01\n" +
+			"jane,Jane,Roe,yesterday\n" +
+			"joe,Joe\n" +
+			"ann,Ann,Lee,2000-01-01\n"
+
+		rows, err := collectRows(t, csvRows(strings.NewReader(body), validate))
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		for _, row := range rows[:3] {
+			assert.ErrorIs(t, row.Err, ErrorInvalidRow, row.Line)
+		}
+		assert.Equal(t, 4, rows[2].Line)
+		assert.NoError(t, rows[3].Err)
+	})
+
+	t.Run("should reject a bare quote in the first column", func(t *testing.T) {
+		body := "username,firstName,lastName,birthday\n" +
+			"a\"b,c,d,2000-01-01\n" +
+			"ann,Ann,Lee,2000-01-01\n"
+
+		rows, err := collectRows(t, csvRows(strings.NewReader(body), validate))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.ErrorIs(t, rows[0].Err, ErrorInvalidRow)
+		assert.Equal(t, 2, rows[0].Line)
+		assert.NoError(t, rows[1].Err)
+		assert.Equal(t, 3, rows[1].Line)
+	})
+
+	t.Run("should reject an unterminated quote in the first column", func(t *testing.T) {
+		body := "username,firstName,lastName,birthday\n" +
+			"\"john,John,Doe,2000-01-01\n"
+
+		rows, err := collectRows(t, csvRows(strings.NewReader(body), validate))
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.ErrorIs(t, rows[0].Err, ErrorInvalidRow)
+		assert.Equal(t, 2, rows[0].Line)
+	})
+
+	t.Run("should reject invalid header", func(t *testing.T) {
+		for _, body := range []string{"", "username,firstName,lastName", "username,firstName,lastName,birthday,password", "username,username,lastName,birthday"} {
+			_, err := collectRows(t, csvRows(strings.NewReader(body), validate))
+			assert.ErrorIs(t, err, ErrorInvalidImport, body)
+		}
+	})
+
+	t.Run("should abort on read error", func(t *testing.T) {
+		r := &failingReader{data: "username,firstName,lastName,birthday\n", err: errors.New("read err")}
+		_, err := collectRows(t, csvRows(r, validate))
+		assert.ErrorContains(t, err, "read err")
+	})
+}
+
+func TestNDJSONRows(t *testing.T) {
+	validate := validator.NewReqValidator().Validate
+
+	t.Run("should decode one member per line", func(t *testing.T) {
+		body := `{"username":"john","firstName":"John","lastName":"Doe","birthday":"2000-01-01T00:00:00Z"}` + "\n\n" +
+			`{"username":"jane","firstName":"Jane"}` + "\n" +
+			`{"username":"joe","password":"x"}` + "\n" +
+			`{"username":"ann"} {"username":"bob"}`
+
+		rows, err := collectRows(t, ndjsonRows(strings.NewReader(body), validate))
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		assert.NoError(t, rows[0].Err)
+		assert.Equal(t, "john", rows[0].Member.Username)
+		assert.Equal(t, 3, rows[1].Line)
+		for _, row := range rows[1:] {
+			assert.ErrorIs(t, row.Err, ErrorInvalidRow, row.Line)
+		}
+	})
+
+	t.Run("should abort on a too long line", func(t *testing.T) {
+		body := `{"username":"` + strings.Repeat("x", maxNDJSONLine) + `"}`
+		_, err := collectRows(t, ndjsonRows(strings.NewReader(body), validate))
+		assert.ErrorIs(t, err, ErrorInvalidImport)
+	})
+}
+
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
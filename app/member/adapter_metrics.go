@@ -0,0 +1,122 @@
+package member
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+)
+
+type metricsService struct {
+	next       Servicer
+	operations *metrics.CounterVec
+}
+
+// NewMetricsService counts member operations by outcome and registers the counter on reg.
+func NewMetricsService(next Servicer, reg *metrics.Registry) *metricsService {
+	operations := metrics.NewCounterVec("member_operations_total", "Member operations by outcome.", "operation", "result")
+	reg.MustRegister(operations)
+	return &metricsService{next: next, operations: operations}
+}
+
+func (s *metricsService) observe(operation string, err error) {
+	result := "success"
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrorMinAge) || errors.Is(err, ErrorMaxAge):
+		result = "invalid_age"
+	case errors.Is(err, ErrorDuplicate):
+		result = "duplicate"
+	case errors.Is(err, ErrorInvalidImport):
+		result = "invalid_import"
+	case errors.Is(err, ErrorInvalidQuery):
+		result = "invalid_query"
+	case errors.Is(err, ErrorInvalidPatch):
+		result = "invalid_patch"
+	case errors.Is(err, ErrorNotDeleted):
+		result = "not_deleted"
+	case errors.Is(err, ErrorVersionMismatch):
+		result = "version_mismatch"
+	case errors.Is(err, ErrorConflict):
+		result = "conflict"
+	case errors.Is(err, ErrorMemberNotFound):
+		result = "not_found"
+	default:
+		result = "error"
+	}
+	s.operations.Inc(operation, result)
+}
+
+func (s *metricsService) Members(ctx context.Context, query MemberQuery) (MemberPage, error) {
+	page, err := s.next.Members(ctx, query)
+	s.observe("members", err)
+	return page, err
+}
+
+func (s *metricsService) Member(ctx context.Context, username string) (Member, error) {
+	member, err := s.next.Member(ctx, username)
+	s.observe("member", err)
+	return member, err
+}
+
+func (s *metricsService) Create(ctx context.Context, member Member) error {
+	err := s.next.Create(ctx, member)
+	s.observe("create", err)
+	return err
+}
+
+func (s *metricsService) Remove(ctx context.Context, username string, version int64) error {
+	err := s.next.Remove(ctx, username, version)
+	s.observe("remove", err)
+	return err
+}
+
+func (s *metricsService) Update(ctx context.Context, username string, version int64, member Member) (Member, error) {
+	member, err := s.next.Update(ctx, username, version, member)
+	s.observe("update", err)
+	return member, err
+}
+
+func (s *metricsService) Patch(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error) {
+	member, err := s.next.Patch(ctx, username, version, patch)
+	s.observe("patch", err)
+	return member, err
+}
+
+func (s *metricsService) Restore(ctx context.Context, username string, version int64) (Member, error) {
+	member, err := s.next.Restore(ctx, username, version)
+	s.observe("restore", err)
+	return member, err
+}
+
+func (s *metricsService) Purge(ctx context.Context, username string) error {
+	err := s.next.Purge(ctx, username)
+	s.observe("purge", err)
+	return err
+}
+
+func (s *metricsService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	n, err := s.next.PurgeDeleted(ctx, retention)
+	s.observe("purge_deleted", err)
+	return n, err
+}
+
+func (s *metricsService) History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error) {
+	page, err := s.next.History(ctx, username, query)
+	s.observe("history", err)
+	return page, err
+}
+
+func (s *metricsService) Import(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error) {
+	report, err := s.next.Import(ctx, rows, mode)
+	s.observe("import", err)
+	return report, err
+}
+
+func (s *metricsService) Export(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error) {
+	members, err := s.next.Export(ctx, query)
+	s.observe("export", err)
+	return members, err
+}
@@ -0,0 +1,90 @@
+package member
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsService(t *testing.T) {
+	member, _ := newFixture()
+
+	scrape := func(t *testing.T, reg *metrics.Registry) string {
+		sb := strings.Builder{}
+		require.NoError(t, reg.WriteText(&sb))
+		return sb.String()
+	}
+
+	t.Run("should count success", func(t *testing.T) {
+		reg := metrics.NewRegistry()
+		svc := newMockServicer(t)
+		page := MemberPage{Members: []Member{member}}
+		svc.EXPECT().Members(contextBackground(), MemberQuery{}).Return(page, nil)
+
+		got, err := NewMetricsService(svc, reg).Members(contextBackground(), MemberQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, page, got)
+		assert.Contains(t, scrape(t, reg), `member_operations_total{operation="members",result="success"} 1`)
+	})
+
+	t.Run("should count business errors by result", func(t *testing.T) {
+		reg := metrics.NewRegistry()
+		svc := newMockServicer(t)
+		svc.EXPECT().Create(contextBackground(), member).Return(ErrorDuplicate)
+		svc.EXPECT().Update(contextBackground(), "john", int64(0), member).Return(Member{}, ErrorMinAge)
+		svc.EXPECT().Member(contextBackground(), "john").Return(Member{}, ErrorMemberNotFound)
+		svc.EXPECT().Remove(contextBackground(), "john", int64(1)).Return(errors.New("db err"))
+		svc.EXPECT().Patch(contextBackground(), "john", int64(0), mock.Anything).Return(Member{}, ErrorInvalidPatch)
+		svc.EXPECT().Patch(contextBackground(), "john", int64(2), mock.Anything).Return(Member{}, ErrorVersionMismatch)
+		svc.EXPECT().Purge(contextBackground(), "john").Return(ErrorNotDeleted)
+		svc.EXPECT().Restore(contextBackground(), "john", int64(0)).Return(member, nil)
+		svc.EXPECT().PurgeDeleted(contextBackground(), time.Hour).Return(3, nil)
+		svc.EXPECT().History(contextBackground(), "john", HistoryQuery{Cursor: "bad"}).Return(HistoryPage{}, ErrorInvalidQuery)
+		svc.EXPECT().Import(contextBackground(), mock.Anything, ImportMode("all")).Return(ImportReport{}, ErrorInvalidImport)
+		svc.EXPECT().Export(contextBackground(), MemberQuery{Sort: "password"}).Return(nil, ErrorInvalidQuery)
+
+		s := NewMetricsService(svc, reg)
+		assert.ErrorIs(t, s.Create(contextBackground(), member), ErrorDuplicate)
+		_, err := s.Update(contextBackground(), "john", 0, member)
+		assert.ErrorIs(t, err, ErrorMinAge)
+		_, err = s.Member(contextBackground(), "john")
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
+		assert.ErrorContains(t, s.Remove(contextBackground(), "john", 1), "db err")
+		_, err = s.Patch(contextBackground(), "john", 0, nil)
+		assert.ErrorIs(t, err, ErrorInvalidPatch)
+		_, err = s.Patch(contextBackground(), "john", 2, nil)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+		assert.ErrorIs(t, s.Purge(contextBackground(), "john"), ErrorNotDeleted)
+		_, err = s.Restore(contextBackground(), "john", 0)
+		assert.NoError(t, err)
+		n, err := s.PurgeDeleted(contextBackground(), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		_, err = s.History(contextBackground(), "john", HistoryQuery{Cursor: "bad"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+		_, err = s.Import(contextBackground(), importRows(), "all")
+		assert.ErrorIs(t, err, ErrorInvalidImport)
+		_, err = s.Export(contextBackground(), MemberQuery{Sort: "password"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+
+		out := scrape(t, reg)
+		assert.Contains(t, out, `member_operations_total{operation="create",result="duplicate"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="update",result="invalid_age"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="member",result="not_found"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="remove",result="error"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="patch",result="invalid_patch"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="patch",result="version_mismatch"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="purge",result="not_deleted"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="restore",result="success"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="purge_deleted",result="success"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="history",result="invalid_query"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="import",result="invalid_import"} 1`)
+		assert.Contains(t, out, `member_operations_total{operation="export",result="invalid_query"} 1`)
+	})
+}
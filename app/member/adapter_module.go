@@ -1,20 +1,73 @@
 package member
 
-import "github.com/jmoiron/sqlx"
+import (
+	"cmp"
+	"embed"
+	"io/fs"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/app"
+	"github.com/kongsakchai/gotemplate/pkg/database"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
 
 type External struct {
-	DB    *sqlx.DB
-	Clock Clock
+	DB      *sqlx.DB
+	Clock   Clock
+	Metrics *metrics.Registry
+	Age     AgePolicy
+
+	// Retention of soft deleted members before they are purged, 0 keeps them forever.
+	Retention     time.Duration
+	PurgeInterval time.Duration
 }
 
 type Module struct {
 	Handler *handler
+
+	service       Servicer
+	retention     time.Duration
+	purgeInterval time.Duration
 }
 
 func NewModule(adp External) *Module {
 	st := NewStorage(adp.DB)
-	sv := NewService(st, adp.Clock)
+	var sv Servicer = NewService(st, database.NewTransactor(adp.DB), adp.Clock, requestCaller{}, adp.Age)
+	if adp.Metrics != nil {
+		sv = NewMetricsService(sv, adp.Metrics)
+	}
+	sv = NewTracingService(sv)
 	h := NewHandler(sv)
 
-	return &Module{Handler: h}
+	return &Module{
+		Handler:       h,
+		service:       sv,
+		retention:     adp.Retention,
+		purgeInterval: cmp.Or(adp.PurgeInterval, time.Hour),
+	}
+}
+
+func (m *Module) Name() string {
+	return "member"
+}
+
+func (m *Module) RegisterRoutes(app *app.EchoApp) {
+	m.Handler.RegisterMemberHandler(app)
+}
+
+func (m *Module) Hooks() []lifecycle.Hook {
+	if m.retention <= 0 {
+		return nil
+	}
+	return []lifecycle.Hook{retentionHook(m.service, m.retention, m.purgeInterval)}
+}
+
+func (m *Module) Migrations() fs.FS {
+	sub, _ := fs.Sub(migrations, "migrations")
+	return sub
 }
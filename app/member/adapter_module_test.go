@@ -5,6 +5,10 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/app"
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/database"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,4 +30,69 @@ func TestNewModule(t *testing.T) {
 		assert.NotNil(t, mod)
 		assert.NotNil(t, mod.Handler)
 	})
+
+	t.Run("should register member metrics", func(t *testing.T) {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		reg := metrics.NewRegistry()
+		NewModule(External{DB: db, Clock: &mockClock2{}, Metrics: reg})
+
+		assert.Error(t, reg.Register(metrics.NewCounterVec("member_operations_total", "")))
+	})
+}
+
+func TestModuleHooks(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	t.Run("should not purge without retention", func(t *testing.T) {
+		mod := NewModule(External{DB: db, Clock: &mockClock2{}})
+		assert.Empty(t, mod.Hooks())
+	})
+
+	t.Run("should add retention hook", func(t *testing.T) {
+		mod := NewModule(External{DB: db, Clock: &mockClock2{}, Retention: 24 * time.Hour})
+
+		var hm app.HookModule = mod
+		hooks := hm.Hooks()
+		require.Len(t, hooks, 1)
+		assert.Equal(t, "member-retention", hooks[0].Name)
+		assert.Equal(t, time.Hour, mod.purgeInterval)
+	})
+}
+
+func TestModule(t *testing.T) {
+	t.Run("should plug into echo app", func(t *testing.T) {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		var mod app.Module = NewModule(External{DB: db, Clock: &mockClock2{}})
+		e := app.NewEchoApp(config.Config{})
+		e.Register(mod)
+
+		assert.Equal(t, "member", mod.Name())
+		assert.Len(t, e.Modules(), 1)
+		assert.NotEmpty(t, e.Router().Routes())
+	})
+
+	t.Run("should migrate member table", func(t *testing.T) {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		mod := NewModule(External{DB: db, Clock: &mockClock2{}})
+		applied, err := database.Migrate(t.Context(), db, mod.Name(), mod.Migrations())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0001", "0002", "0003", "0004"}, applied)
+
+		_, err = db.Exec("INSERT INTO member (username, first_name, last_name, birthday, register_date) VALUES ('john', 'John', 'Doe', '2000-01-01', '2025-01-01')")
+		assert.NoError(t, err)
+		_, err = db.Exec("INSERT INTO member_history (username, version, action, actor, trace_id, diff, changed_at) VALUES ('john', 1, 'create', 'admin', '', '[]', '2025-01-01')")
+		assert.NoError(t, err)
+	})
 }
@@ -3,10 +3,19 @@ package member
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/pkg/database"
 	"github.com/kongsakchai/gotemplate/pkg/errs"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 )
 
 type storage struct {
@@ -18,72 +27,588 @@ func NewStorage(db *sqlx.DB) *storage {
 }
 
 type memberRecord struct {
-	Username     string    `db:"username"`
-	FirstName    string    `db:"first_name"`
-	LastName     string    `db:"last_name"`
-	Birthday     time.Time `db:"birthday"`
-	RegisterDate time.Time `db:"register_date"`
+	Username     string       `db:"username"`
+	FirstName    string       `db:"first_name"`
+	LastName     string       `db:"last_name"`
+	Birthday     time.Time    `db:"birthday"`
+	RegisterDate time.Time    `db:"register_date"`
+	Version      int64        `db:"version"`
+	DeletedAt    sql.NullTime `db:"deleted_at"`
 }
 
 func (m memberRecord) ToMember() Member {
-	return Member(m)
+	return Member{
+		Username:     m.Username,
+		FirstName:    m.FirstName,
+		LastName:     m.LastName,
+		Birthday:     m.Birthday,
+		RegisterDate: m.RegisterDate,
+		Version:      m.Version,
+		DeletedAt:    m.DeletedAt.Time,
+	}
+}
+
+func (s *storage) startSpan(ctx context.Context, operation, query string) (context.Context, *trace.Span) {
+	return trace.Start(ctx, "member.storage."+operation,
+		trace.WithKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			slog.String("db.system", s.db.DriverName()),
+			slog.String("db.operation", operation),
+			slog.String("db.statement", strings.TrimSpace(query)),
+		),
+	)
+}
+
+var sortColumns = map[SortField]string{
+	SortUsername:     "username",
+	SortFirstName:    "first_name",
+	SortLastName:     "last_name",
+	SortBirthday:     "birthday",
+	SortRegisterDate: "register_date",
+}
+
+var likeReplacer = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// memberFilter builds the WHERE clause shared by the page and the total count.
+func memberFilter(q MemberQuery) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	if !q.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if q.NamePrefix != "" {
+		prefix := likeReplacer.Replace(q.NamePrefix) + "%"
+		conds = append(conds, "(first_name LIKE ? ESCAPE '!' OR last_name LIKE ? ESCAPE '!')")
+		args = append(args, prefix, prefix)
+	}
+	for _, r := range []struct {
+		column string
+		op     string
+		value  time.Time
+	}{
+		{"birthday", ">=", q.BirthdayFrom},
+		{"birthday", "<", nextDay(q.BirthdayTo)},
+		{"register_date", ">=", q.RegisteredFrom},
+		// the upper bounds are dates, so the whole last day matches
+		{"register_date", "<", nextDay(q.RegisteredTo)},
+	} {
+		if !r.value.IsZero() {
+			conds = append(conds, r.column+" "+r.op+" ?")
+			args = append(args, r.value)
+		}
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
 }
 
-func (s *storage) Members(ctx context.Context) ([]Member, error) {
+// nextDay is the start of the day after date, zero stays zero.
+func nextDay(date time.Time) time.Time {
+	if date.IsZero() {
+		return date
+	}
+	return date.AddDate(0, 0, 1)
+}
+
+// Members pages with a keyset on (sort column, username), fetching one extra row to know whether
+// another page follows.
+func (s *storage) Members(ctx context.Context, q MemberQuery) (MemberPage, error) {
+	column, ok := sortColumns[q.Sort]
+	if !ok {
+		return MemberPage{}, ErrorInvalidQuery
+	}
+	after, err := q.After()
+	if err != nil {
+		return MemberPage{}, err
+	}
+
+	where, filterArgs := memberFilter(q)
+	query := "SELECT * FROM member" + where
+	args := slices.Clone(filterArgs)
+
+	if after != nil {
+		op := ">"
+		if q.Desc {
+			op = "<"
+		}
+		var value any = after.Value
+		if q.Sort.IsTime() {
+			if value, err = time.Parse(time.RFC3339Nano, after.Value); err != nil {
+				return MemberPage{}, ErrorInvalidQuery
+			}
+		}
+
+		keyset := "username " + op + " ?"
+		keyArgs := []any{after.Username}
+		if column != "username" {
+			keyset = fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND username %[2]s ?))", column, op)
+			keyArgs = []any{value, value, after.Username}
+		}
+		if where == "" {
+			query += " WHERE " + keyset
+		} else {
+			query += " AND " + keyset
+		}
+		args = append(args, keyArgs...)
+	}
+
+	query += memberOrder(column, q.Desc) + " LIMIT ?"
+	args = append(args, q.Limit+1)
+	if after == nil && q.Page > 1 {
+		query += " OFFSET ?"
+		args = append(args, (q.Page-1)*q.Limit)
+	}
+
+	ctx, span := s.startSpan(ctx, "Members", query)
+	defer span.End()
+
 	var result []memberRecord
-	err := s.db.SelectContext(ctx, &result, "SELECT * FROM member")
+	if err := s.conn(ctx).SelectContext(ctx, &result, query, args...); err != nil {
+		span.RecordError(err)
+		return MemberPage{}, storageError(err)
+	}
 
-	members := []Member{}
-	for _, m := range result {
-		members = append(members, m.ToMember())
+	page := MemberPage{Members: []Member{}}
+	for _, m := range result[:min(len(result), q.Limit)] {
+		page.Members = append(page.Members, m.ToMember())
+	}
+	if len(result) > q.Limit {
+		page.NextCursor = q.NextCursor(page.Members[len(page.Members)-1])
 	}
 
-	return members, errs.From(err)
+	if q.WithTotal {
+		var total int64
+		if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*) FROM member"+where, filterArgs...); err != nil {
+			span.RecordError(err)
+			return MemberPage{}, storageError(err)
+		}
+		page.Total = &total
+	}
+	return page, nil
+}
+
+// memberOrder sorts by column, breaking ties by username.
+func memberOrder(column string, desc bool) string {
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	order := fmt.Sprintf(" ORDER BY %s %s", column, dir)
+	if column != "username" {
+		order += ", username " + dir
+	}
+	return order
+}
+
+// Stream reads the members matching the query filters one row at a time in sort order, paging
+// fields are ignored. It holds a connection until the iteration ends.
+func (s *storage) Stream(ctx context.Context, q MemberQuery) iter.Seq2[Member, error] {
+	return func(yield func(Member, error) bool) {
+		column, ok := sortColumns[q.Sort]
+		if !ok {
+			yield(Member{}, ErrorInvalidQuery)
+			return
+		}
+		where, args := memberFilter(q)
+		query := "SELECT * FROM member" + where + memberOrder(column, q.Desc)
+
+		ctx, span := s.startSpan(ctx, "Stream", query)
+		defer span.End()
+
+		rows, err := s.conn(ctx).QueryxContext(ctx, query, args...)
+		if err != nil {
+			span.RecordError(err)
+			yield(Member{}, storageError(err))
+			return
+		}
+		defer rows.Close()
+
+		n := 0
+		for rows.Next() {
+			var r memberRecord
+			if err := rows.StructScan(&r); err != nil {
+				span.RecordError(err)
+				yield(Member{}, storageError(err))
+				return
+			}
+			n++
+			if !yield(r.ToMember(), nil) {
+				break
+			}
+		}
+		span.SetAttributes(slog.Int("db.rows", n))
+		if err := rows.Err(); err != nil {
+			span.RecordError(err)
+			yield(Member{}, storageError(err))
+		}
+	}
 }
 
 func (s *storage) Member(ctx context.Context, username string) (Member, bool, error) {
+	query := "SELECT * FROM member WHERE username = ?" + database.ForUpdate(ctx, s.db)
+	ctx, span := s.startSpan(ctx, "Member", query)
+	defer span.End()
+
 	member := memberRecord{}
-	err := s.db.GetContext(ctx, &member, "SELECT * FROM member WHERE username = ?", username)
+	err := s.conn(ctx).GetContext(ctx, &member, query, username)
 	if err == sql.ErrNoRows {
 		return member.ToMember(), false, nil
 	}
-	return member.ToMember(), err == nil, errs.From(err)
+	span.RecordError(err)
+	return member.ToMember(), err == nil, storageError(err)
 }
 
-func (s *storage) Create(ctx context.Context, member Member) error {
-	query := `
+// inTx runs fn in a transaction committing only when it succeeds, or joins the transaction
+// already carried by ctx.
+func (s *storage) inTx(ctx context.Context, fn func(ctx context.Context, tx database.Executor) error) error {
+	var fnErr error
+	err := database.InTx(ctx, s.db, func(ctx context.Context) error {
+		fnErr = fn(ctx, database.Conn(ctx, s.db))
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		// begin or commit failed
+		return storageError(err)
+	}
+	return err
+}
+
+// storageError translates constraint violations and lost races into member errors, keeping the
+// driver error wrapped.
+func storageError(err error) error {
+	err = database.Classify(err)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, database.ErrUniqueViolation):
+		return fmt.Errorf("%w: %w", ErrorDuplicate, err)
+	case database.Retryable(err):
+		return fmt.Errorf("%w: %w", ErrorConflict, err)
+	}
+	return errs.From(err)
+}
+
+// conn is the ambient transaction of ctx or the database.
+func (s *storage) conn(ctx context.Context) database.Executor {
+	return database.Conn(ctx, s.db)
+}
+
+const createQuery = `
 	INSERT INTO member (username, first_name, last_name, birthday, register_date)
 	VALUES (:username, :first_name, :last_name, :birthday, :register_date)`
 
-	_, err := s.db.NamedExecContext(ctx, query, map[string]any{
+func (s *storage) Create(ctx context.Context, member Member, change Change) error {
+	ctx, span := s.startSpan(ctx, "Create", createQuery)
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		return createTx(ctx, tx, member, change)
+	})
+	span.RecordError(err)
+	return err
+}
+
+func (s *storage) CreateAll(ctx context.Context, members []Member, changes []Change) error {
+	if len(members) != len(changes) {
+		return fmt.Errorf("create members: %d members with %d changes", len(members), len(changes))
+	}
+	ctx, span := s.startSpan(ctx, "CreateAll", createQuery)
+	span.SetAttributes(slog.Int("db.rows", len(members)))
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		for i, member := range members {
+			if err := createTx(ctx, tx, member, changes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	span.RecordError(err)
+	return err
+}
+
+func createTx(ctx context.Context, tx database.Executor, member Member, change Change) error {
+	_, err := tx.NamedExecContext(ctx, createQuery, map[string]any{
 		"username":      member.Username,
-		"first_name":    member.Username,
-		"last_name":     member.Username,
-		"birthday":      member.Username,
+		"first_name":    member.FirstName,
+		"last_name":     member.LastName,
+		"birthday":      member.Birthday,
 		"register_date": member.RegisterDate,
 	})
-
-	return errs.From(err)
+	if err != nil {
+		return storageError(err)
+	}
+	return insertChange(ctx, tx, change)
 }
 
-func (s *storage) Update(ctx context.Context, member Member) error {
+func (s *storage) Update(ctx context.Context, member Member, change Change) error {
 	query := `
-	UPDATE member SET first_name=?, last_name=?, birthday=?, register_date=? WHERE username=?`
-
-	_, err := s.db.ExecContext(ctx, query,
-		member.FirstName,
-		member.LastName,
-		member.Birthday,
-		member.RegisterDate,
-		member.Username,
-	)
+	UPDATE member SET first_name=?, last_name=?, birthday=?, register_date=?, version=version+1
+	WHERE username=? AND version=? AND deleted_at IS NULL`
+	ctx, span := s.startSpan(ctx, "Update", query)
+	defer span.End()
 
-	return errs.From(err)
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		result, err := tx.ExecContext(ctx, query,
+			member.FirstName,
+			member.LastName,
+			member.Birthday,
+			member.RegisterDate,
+			member.Username,
+			member.Version,
+		)
+		if err := versionChecked(result, err); err != nil {
+			return err
+		}
+		return insertChange(ctx, tx, change)
+	})
+	span.RecordError(err)
+	return err
+}
+
+// versionChecked reports ErrorVersionMismatch when a versioned write matched no row.
+func versionChecked(result sql.Result, err error) error {
+	if err != nil {
+		return storageError(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return storageError(err)
+	}
+	if n == 0 {
+		return ErrorVersionMismatch
+	}
+	return nil
 }
 
-func (s *storage) Remove(ctx context.Context, username string) error {
-	query := `DELETE FROM member WHERE username = ?`
+var patchColumns = map[Field]string{
+	FieldFirstName:    "first_name",
+	FieldLastName:     "last_name",
+	FieldBirthday:     "birthday",
+	FieldRegisterDate: "register_date",
+}
 
-	_, err := s.db.ExecContext(ctx, query, username)
-	return errs.From(err)
+func patchValue(member Member, field Field) any {
+	switch field {
+	case FieldFirstName:
+		return member.FirstName
+	case FieldLastName:
+		return member.LastName
+	case FieldBirthday:
+		return member.Birthday
+	default:
+		return member.RegisterDate
+	}
+}
+
+// Patch writes only the given fields of member.
+func (s *storage) Patch(ctx context.Context, member Member, fields []Field, change Change) error {
+	sets := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for _, f := range fields {
+		column, ok := patchColumns[f]
+		if !ok {
+			return fmt.Errorf("patch member: unknown field %q", f)
+		}
+		sets = append(sets, column+"=?")
+		args = append(args, patchValue(member, f))
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	query := "UPDATE member SET " + strings.Join(sets, ", ") + ", version=version+1 WHERE username=? AND version=? AND deleted_at IS NULL"
+	ctx, span := s.startSpan(ctx, "Patch", query)
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		result, err := tx.ExecContext(ctx, query, append(args, member.Username, member.Version)...)
+		if err := versionChecked(result, err); err != nil {
+			return err
+		}
+		return insertChange(ctx, tx, change)
+	})
+	span.RecordError(err)
+	return err
+}
+
+func (s *storage) Remove(ctx context.Context, member Member, change Change) error {
+	query := `UPDATE member SET deleted_at=?, version=version+1 WHERE username=? AND version=? AND deleted_at IS NULL`
+	ctx, span := s.startSpan(ctx, "Remove", query)
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		result, err := tx.ExecContext(ctx, query, member.DeletedAt, member.Username, member.Version)
+		if err := versionChecked(result, err); err != nil {
+			return err
+		}
+		return insertChange(ctx, tx, change)
+	})
+	span.RecordError(err)
+	return err
+}
+
+func (s *storage) Restore(ctx context.Context, member Member, change Change) error {
+	query := `UPDATE member SET deleted_at=NULL, version=version+1 WHERE username=? AND version=? AND deleted_at IS NOT NULL`
+	ctx, span := s.startSpan(ctx, "Restore", query)
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		result, err := tx.ExecContext(ctx, query, member.Username, member.Version)
+		if err := versionChecked(result, err); err != nil {
+			return err
+		}
+		return insertChange(ctx, tx, change)
+	})
+	span.RecordError(err)
+	return err
+}
+
+// Purge drops a soft deleted member and its history, ErrorMemberNotFound when there is no such
+// deleted member.
+func (s *storage) Purge(ctx context.Context, username string) error {
+	query := `DELETE FROM member WHERE username=? AND deleted_at IS NOT NULL`
+	ctx, span := s.startSpan(ctx, "Purge", query)
+	defer span.End()
+
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		result, err := tx.ExecContext(ctx, query, username)
+		if err != nil {
+			return storageError(err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return storageError(err)
+		}
+		if n == 0 {
+			// purged meanwhile or never soft deleted
+			return ErrorMemberNotFound
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM member_history WHERE username=?`, username)
+		return storageError(err)
+	})
+	span.RecordError(err)
+	return err
+}
+
+func (s *storage) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM member WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+	ctx, span := s.startSpan(ctx, "PurgeDeleted", query)
+	defer span.End()
+
+	var n int64
+	err := s.inTx(ctx, func(ctx context.Context, tx database.Executor) error {
+		_, err := tx.ExecContext(ctx, `
+		DELETE FROM member_history WHERE username IN (
+			SELECT username FROM member WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		)`, before)
+		if err != nil {
+			return storageError(err)
+		}
+		result, err := tx.ExecContext(ctx, query, before)
+		if err != nil {
+			return storageError(err)
+		}
+		n, err = result.RowsAffected()
+		return storageError(err)
+	})
+	span.RecordError(err)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+type changeRecord struct {
+	Username  string    `db:"username"`
+	Version   int64     `db:"version"`
+	Action    string    `db:"action"`
+	Actor     string    `db:"actor"`
+	TraceID   string    `db:"trace_id"`
+	Diff      string    `db:"diff"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+func (c changeRecord) ToChange() (Change, error) {
+	change := Change{
+		Username:  c.Username,
+		Version:   c.Version,
+		Action:    Action(c.Action),
+		Actor:     c.Actor,
+		TraceID:   c.TraceID,
+		ChangedAt: c.ChangedAt,
+	}
+	if err := json.Unmarshal([]byte(c.Diff), &change.Diff); err != nil {
+		return Change{}, fmt.Errorf("member history diff: %w", err)
+	}
+	return change, nil
+}
+
+func insertChange(ctx context.Context, tx database.Executor, change Change) error {
+	diff, err := json.Marshal(change.Diff)
+	if err != nil {
+		return fmt.Errorf("member history diff: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO member_history (username, version, action, actor, trace_id, diff, changed_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		change.Username,
+		change.Version,
+		change.Action,
+		change.Actor,
+		change.TraceID,
+		string(diff),
+		change.ChangedAt,
+	)
+	if errors.Is(database.Classify(err), database.ErrUniqueViolation) {
+		// another write already recorded this version of the member
+		return fmt.Errorf("%w: %w", ErrorVersionMismatch, err)
+	}
+	return storageError(err)
+}
+
+// History pages newest first with a keyset on version, fetching one extra row to know whether
+// another page follows.
+func (s *storage) History(ctx context.Context, username string, q HistoryQuery) (HistoryPage, error) {
+	before, err := q.Before()
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	query := "SELECT * FROM member_history WHERE username = ?"
+	args := []any{username}
+	if before > 0 {
+		query += " AND version < ?"
+		args = append(args, before)
+	}
+	query += " ORDER BY version DESC LIMIT ?"
+	args = append(args, q.Limit+1)
+
+	ctx, span := s.startSpan(ctx, "History", query)
+	defer span.End()
+
+	var result []changeRecord
+	if err := s.conn(ctx).SelectContext(ctx, &result, query, args...); err != nil {
+		span.RecordError(err)
+		return HistoryPage{}, storageError(err)
+	}
+
+	page := HistoryPage{Changes: []Change{}}
+	for _, r := range result[:min(len(result), q.Limit)] {
+		change, err := r.ToChange()
+		if err != nil {
+			span.RecordError(err)
+			return HistoryPage{}, err
+		}
+		page.Changes = append(page.Changes, change)
+	}
+	if len(result) > q.Limit {
+		page.NextCursor = q.NextCursor(page.Changes[len(page.Changes)-1])
+	}
+	return page, nil
 }
@@ -1,10 +1,15 @@
 package member
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/pkg/database"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -16,6 +21,8 @@ func setupStorage(t *testing.T) *storage {
 
 	db, err := sqlx.Open("sqlite", ":memory:")
 	require.NoError(t, err)
+	// every connection opens its own in-memory database
+	db.SetMaxOpenConns(1)
 	t.Cleanup(func() { db.Close() })
 
 	_, err = db.Exec(`CREATE TABLE member (
@@ -23,22 +30,170 @@ func setupStorage(t *testing.T) *storage {
 		first_name TEXT,
 		last_name TEXT,
 		birthday datetime,
-		register_date datetime
+		register_date datetime,
+		version INTEGER NOT NULL DEFAULT 1,
+		deleted_at datetime
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE member_history (
+		username TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		trace_id TEXT NOT NULL,
+		diff TEXT NOT NULL,
+		changed_at datetime NOT NULL,
+		PRIMARY KEY (username, version)
 	)`)
 	require.NoError(t, err)
 
 	return NewStorage(db)
 }
 
+func newChange(username string, version int64, action Action) Change {
+	return Change{
+		Username:  username,
+		Version:   version,
+		Action:    action,
+		Actor:     "admin",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		Diff:      []FieldChange{},
+		ChangedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func historyVersions(t *testing.T, s *storage, username string) []int64 {
+	t.Helper()
+	var versions []int64
+	require.NoError(t, s.db.SelectContext(t.Context(), &versions,
+		"SELECT version FROM member_history WHERE username = ? ORDER BY version", username))
+	return versions
+}
+
 func TestStorageCreate(t *testing.T) {
 	t.Run("should create member successfully", func(t *testing.T) {
 		s := setupStorage(t)
 
 		m := Member{
-			Username: "newuser",
+			Username:     "newuser",
+			FirstName:    "New",
+			LastName:     "User",
+			Birthday:     time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			RegisterDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
-		err := s.Create(t.Context(), m)
+		err := s.Create(t.Context(), m, newChange("newuser", 1, ActionCreate))
 		assert.NoError(t, err)
+
+		got, found, err := s.Member(t.Context(), "newuser")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "New", got.FirstName)
+		assert.Equal(t, "User", got.LastName)
+		assert.Equal(t, m.Birthday, got.Birthday.UTC())
+		assert.Equal(t, []int64{1}, historyVersions(t, s, "newuser"))
+	})
+
+	t.Run("should roll back member when history fails", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member_history (username, version, action, actor, trace_id, diff, changed_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			"newuser", 1, "create", "admin", "", "[]", "2025-01-01T00:00:00Z")
+		require.NoError(t, err)
+
+		err = s.Create(t.Context(), Member{Username: "newuser"}, newChange("newuser", 1, ActionCreate))
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+
+		_, found, err := s.Member(t.Context(), "newuser")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should report a taken username as duplicate", func(t *testing.T) {
+		s := setupStorage(t)
+		require.NoError(t, s.Create(t.Context(), Member{Username: "newuser"}, newChange("newuser", 1, ActionCreate)))
+
+		err := s.Create(t.Context(), Member{Username: "newuser"}, newChange("newuser", 2, ActionCreate))
+		assert.ErrorIs(t, err, ErrorDuplicate)
+		assert.ErrorIs(t, err, database.ErrUniqueViolation)
+		assert.Equal(t, []int64{1}, historyVersions(t, s, "newuser"))
+	})
+
+	t.Run("should join the ambient transaction", func(t *testing.T) {
+		s := setupStorage(t)
+
+		err := database.InTx(t.Context(), s.db, func(ctx context.Context) error {
+			err := s.Create(ctx, Member{Username: "newuser"}, newChange("newuser", 1, ActionCreate))
+			require.NoError(t, err)
+			_, found, err := s.Member(ctx, "newuser")
+			require.NoError(t, err)
+			assert.True(t, found)
+			return errors.New("rollback")
+		})
+		assert.EqualError(t, err, "rollback")
+
+		_, found, err := s.Member(t.Context(), "newuser")
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Empty(t, historyVersions(t, s, "newuser"))
+	})
+}
+
+func TestStorageError(t *testing.T) {
+	t.Run("should translate unique violations to duplicate", func(t *testing.T) {
+		err := storageError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'john'"})
+		assert.ErrorIs(t, err, ErrorDuplicate)
+		assert.ErrorIs(t, err, database.ErrUniqueViolation)
+	})
+
+	t.Run("should translate lost races to conflict", func(t *testing.T) {
+		assert.ErrorIs(t, storageError(&mysql.MySQLError{Number: 1213}), ErrorConflict)
+		assert.ErrorIs(t, storageError(&pq.Error{Code: "40001"}), ErrorConflict)
+	})
+
+	t.Run("should keep other errors", func(t *testing.T) {
+		err := storageError(&pq.Error{Code: "23502"})
+		assert.ErrorIs(t, err, database.ErrNotNullViolation)
+		assert.NotErrorIs(t, err, ErrorDuplicate)
+		assert.NotErrorIs(t, err, ErrorConflict)
+		assert.NoError(t, storageError(nil))
+	})
+}
+
+func TestStorageCreateAll(t *testing.T) {
+	t.Run("should create every member", func(t *testing.T) {
+		s := setupStorage(t)
+
+		err := s.CreateAll(t.Context(),
+			[]Member{{Username: "john"}, {Username: "jane"}},
+			[]Change{newChange("john", 1, ActionCreate), newChange("jane", 1, ActionCreate)})
+		require.NoError(t, err)
+
+		for _, username := range []string{"john", "jane"} {
+			_, found, err := s.Member(t.Context(), username)
+			require.NoError(t, err)
+			assert.True(t, found, username)
+			assert.Equal(t, []int64{1}, historyVersions(t, s, username))
+		}
+	})
+
+	t.Run("should create none when one fails", func(t *testing.T) {
+		s := setupStorage(t)
+
+		err := s.CreateAll(t.Context(),
+			[]Member{{Username: "john"}, {Username: "john"}},
+			[]Change{newChange("john", 1, ActionCreate), newChange("john", 1, ActionCreate)})
+		assert.ErrorIs(t, err, ErrorDuplicate)
+
+		_, found, err := s.Member(t.Context(), "john")
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Empty(t, historyVersions(t, s, "john"))
+	})
+
+	t.Run("should reject unmatched changes", func(t *testing.T) {
+		s := setupStorage(t)
+		assert.Error(t, s.CreateAll(t.Context(), []Member{{Username: "john"}}, nil))
 	})
 }
 
@@ -57,22 +212,236 @@ func TestStorageUpdate(t *testing.T) {
 			LastName:     "Name",
 			Birthday:     time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
 			RegisterDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Version:      1,
 		}
-		err = s.Update(t.Context(), m)
+		err = s.Update(t.Context(), m, newChange("toupdate", 2, ActionUpdate))
 		assert.NoError(t, err)
+
+		got, _, err := s.Member(t.Context(), "toupdate")
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", got.FirstName)
+		assert.Equal(t, int64(2), got.Version)
+		assert.Equal(t, []int64{2}, historyVersions(t, s, "toupdate"))
+	})
+
+	t.Run("should reject stale version", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date, version) VALUES (?, ?, ?, ?, ?, ?)",
+			"toupdate", "Old", "Name", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z", 2)
+		require.NoError(t, err)
+
+		err = s.Update(t.Context(), Member{Username: "toupdate", FirstName: "Updated", Version: 1}, newChange("toupdate", 2, ActionUpdate))
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+
+		got, _, err := s.Member(t.Context(), "toupdate")
+		require.NoError(t, err)
+		assert.Equal(t, "Old", got.FirstName)
+		assert.Empty(t, historyVersions(t, s, "toupdate"))
+	})
+}
+
+func TestStoragePatch(t *testing.T) {
+	t.Run("should write only the given fields", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date) VALUES (?, ?, ?, ?, ?)",
+			"topatch", "Old", "Name", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z")
+		require.NoError(t, err)
+
+		m := Member{Username: "topatch", FirstName: "New", LastName: "Ignored", Version: 1}
+		require.NoError(t, s.Patch(t.Context(), m, []Field{FieldFirstName}, newChange("topatch", 2, ActionUpdate)))
+
+		got, found, err := s.Member(t.Context(), "topatch")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "New", got.FirstName)
+		assert.Equal(t, "Name", got.LastName)
+		assert.Equal(t, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), got.Birthday.UTC())
+		assert.Equal(t, int64(2), got.Version)
+		assert.Equal(t, []int64{2}, historyVersions(t, s, "topatch"))
+
+		assert.ErrorIs(t, s.Patch(t.Context(), m, []Field{FieldFirstName}, newChange("topatch", 2, ActionUpdate)), ErrorVersionMismatch)
+	})
+
+	t.Run("should skip when nothing changed", func(t *testing.T) {
+		s := setupStorage(t)
+		assert.NoError(t, s.Patch(t.Context(), Member{Username: "nobody"}, nil, Change{}))
+	})
+
+	t.Run("should reject unknown field", func(t *testing.T) {
+		s := setupStorage(t)
+		assert.Error(t, s.Patch(t.Context(), Member{Username: "nobody"}, []Field{"username"}, Change{}))
 	})
 }
 
 func TestStorageRemove(t *testing.T) {
-	t.Run("should remove existing member", func(t *testing.T) {
+	deletedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should soft delete existing member", func(t *testing.T) {
 		s := setupStorage(t)
 
 		_, err := s.db.ExecContext(t.Context(),
-			"INSERT INTO member (username) VALUES (?)", "todelete")
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date) VALUES (?, ?, ?, ?, ?)",
+			"todelete", "Old", "Name", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z")
 		require.NoError(t, err)
 
-		err = s.Remove(t.Context(), "todelete")
+		m := Member{Username: "todelete", Version: 1, DeletedAt: deletedAt}
+		err = s.Remove(t.Context(), m, newChange("todelete", 2, ActionDelete))
 		assert.NoError(t, err)
+
+		member, found, err := s.Member(t.Context(), "todelete")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.True(t, member.Deleted())
+		assert.Equal(t, deletedAt, member.DeletedAt.UTC())
+		assert.Equal(t, int64(2), member.Version)
+		assert.Equal(t, []int64{2}, historyVersions(t, s, "todelete"))
+
+		m.Version = 2
+		assert.ErrorIs(t, s.Remove(t.Context(), m, newChange("todelete", 3, ActionDelete)), ErrorVersionMismatch, "already deleted")
+	})
+
+	t.Run("should remove only the given version", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, version) VALUES (?, ?)", "todelete", 2)
+		require.NoError(t, err)
+
+		m := Member{Username: "todelete", Version: 1, DeletedAt: deletedAt}
+		assert.ErrorIs(t, s.Remove(t.Context(), m, newChange("todelete", 3, ActionDelete)), ErrorVersionMismatch)
+		m.Version = 2
+		assert.NoError(t, s.Remove(t.Context(), m, newChange("todelete", 3, ActionDelete)))
+	})
+}
+
+func TestStorageRestore(t *testing.T) {
+	t.Run("should restore deleted member", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date, version, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			"torestore", "Old", "Name", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z", 2, "2025-02-01T00:00:00Z")
+		require.NoError(t, err)
+
+		change := newChange("torestore", 3, ActionRestore)
+		assert.ErrorIs(t, s.Restore(t.Context(), Member{Username: "torestore", Version: 1}, change), ErrorVersionMismatch)
+		require.NoError(t, s.Restore(t.Context(), Member{Username: "torestore", Version: 2}, change))
+
+		member, _, err := s.Member(t.Context(), "torestore")
+		require.NoError(t, err)
+		assert.False(t, member.Deleted())
+		assert.Equal(t, int64(3), member.Version)
+		assert.Equal(t, []int64{3}, historyVersions(t, s, "torestore"))
+	})
+}
+
+func TestStoragePurge(t *testing.T) {
+	insert := func(t *testing.T, s *storage, username string, deletedAt any) {
+		t.Helper()
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date, deleted_at) VALUES (?, ?, ?, ?, ?, ?)",
+			username, "First", "Last", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z", deletedAt)
+		require.NoError(t, err)
+		_, err = s.db.ExecContext(t.Context(),
+			"INSERT INTO member_history (username, version, action, actor, trace_id, diff, changed_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			username, 1, "create", "admin", "", "[]", "2025-01-01T00:00:00Z")
+		require.NoError(t, err)
+	}
+	exists := func(t *testing.T, s *storage, username string) bool {
+		t.Helper()
+		_, found, err := s.Member(t.Context(), username)
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("should purge only deleted member", func(t *testing.T) {
+		s := setupStorage(t)
+		insert(t, s, "active", nil)
+		insert(t, s, "deleted", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.ErrorIs(t, s.Purge(t.Context(), "active"), ErrorMemberNotFound)
+		require.NoError(t, s.Purge(t.Context(), "deleted"))
+
+		assert.True(t, exists(t, s, "active"))
+		assert.False(t, exists(t, s, "deleted"))
+		assert.Equal(t, []int64{1}, historyVersions(t, s, "active"))
+		assert.Empty(t, historyVersions(t, s, "deleted"))
+	})
+
+	t.Run("should report missing member", func(t *testing.T) {
+		s := setupStorage(t)
+		insert(t, s, "deleted", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, s.Purge(t.Context(), "deleted"))
+
+		assert.ErrorIs(t, s.Purge(t.Context(), "deleted"), ErrorMemberNotFound)
+		assert.ErrorIs(t, s.Purge(t.Context(), "unknown"), ErrorMemberNotFound)
+	})
+
+	t.Run("should purge members deleted before cutoff", func(t *testing.T) {
+		s := setupStorage(t)
+		insert(t, s, "active", nil)
+		insert(t, s, "old", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		insert(t, s, "recent", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+		n, err := s.PurgeDeleted(t.Context(), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		assert.True(t, exists(t, s, "active"))
+		assert.False(t, exists(t, s, "old"))
+		assert.True(t, exists(t, s, "recent"))
+		assert.Empty(t, historyVersions(t, s, "old"))
+		assert.Equal(t, []int64{1}, historyVersions(t, s, "recent"))
+	})
+}
+
+func TestStorageHistory(t *testing.T) {
+	t.Run("should page newest first", func(t *testing.T) {
+		s := setupStorage(t)
+
+		created := newChange("john", 1, ActionCreate)
+		created.Diff = []FieldChange{{Field: FieldFirstName, Before: nil, After: "John"}}
+		require.NoError(t, s.Create(t.Context(), Member{Username: "john", FirstName: "John"}, created))
+		for v := range int64(2) {
+			m := Member{Username: "john", FirstName: "John", Version: v + 1}
+			require.NoError(t, s.Update(t.Context(), m, newChange("john", v+2, ActionUpdate)))
+		}
+
+		page, err := s.History(t.Context(), "john", HistoryQuery{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Changes, 2)
+		assert.Equal(t, int64(3), page.Changes[0].Version)
+		assert.Equal(t, int64(2), page.Changes[1].Version)
+		assert.NotEmpty(t, page.NextCursor)
+
+		page, err = s.History(t.Context(), "john", HistoryQuery{Limit: 2, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, page.Changes, 1)
+		assert.Equal(t, created.Diff, page.Changes[0].Diff)
+		assert.Equal(t, ActionCreate, page.Changes[0].Action)
+		assert.Equal(t, "admin", page.Changes[0].Actor)
+		assert.Equal(t, created.TraceID, page.Changes[0].TraceID)
+		assert.Equal(t, created.ChangedAt, page.Changes[0].ChangedAt.UTC())
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should return empty page for unknown member", func(t *testing.T) {
+		s := setupStorage(t)
+
+		page, err := s.History(t.Context(), "nobody", HistoryQuery{Limit: 20})
+		require.NoError(t, err)
+		assert.Equal(t, HistoryPage{Changes: []Change{}}, page)
+	})
+
+	t.Run("should reject invalid cursor", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.History(t.Context(), "john", HistoryQuery{Limit: 20, Cursor: "!"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
 	})
 }
 
@@ -84,17 +453,194 @@ func TestNewStorage(t *testing.T) {
 }
 
 func TestStorageMembers(t *testing.T) {
+	seed := func(t *testing.T, s *storage) {
+		t.Helper()
+		for _, m := range []Member{
+			{Username: "alice", FirstName: "Alice", LastName: "Smith", Birthday: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), RegisterDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Username: "bob", FirstName: "Bob", LastName: "Allen", Birthday: time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC), RegisterDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+			{Username: "carol", FirstName: "Carol", LastName: "Al_ford", Birthday: time.Date(2000, 7, 1, 0, 0, 0, 0, time.UTC), RegisterDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Username: "dave", FirstName: "Dave", LastName: "Brown", Birthday: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), RegisterDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		} {
+			_, err := s.db.ExecContext(t.Context(),
+				"INSERT INTO member (username, first_name, last_name, birthday, register_date) VALUES (?, ?, ?, ?, ?)",
+				m.Username, m.FirstName, m.LastName, m.Birthday, m.RegisterDate)
+			require.NoError(t, err)
+		}
+	}
+	usernames := func(page MemberPage) []string {
+		names := []string{}
+		for _, m := range page.Members {
+			names = append(names, m.Username)
+		}
+		return names
+	}
+
 	t.Run("should return members", func(t *testing.T) {
 		s := setupStorage(t)
+		seed(t, s)
 
-		_, err := s.db.ExecContext(t.Context(),
-			"INSERT INTO member (username, first_name, last_name, birthday, register_date) VALUES (?, ?, ?, ?, ?)",
-			"toupdate", "Old", "Name", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z")
+		page, err := s.Members(t.Context(), MemberQuery{Limit: 10, Sort: SortUsername})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob", "carol", "dave"}, usernames(page))
+		assert.Empty(t, page.NextCursor)
+		assert.Nil(t, page.Total)
+	})
+
+	t.Run("should page with cursor", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+
+		q := MemberQuery{Limit: 3, Sort: SortBirthday, Desc: true}
+		page, err := s.Members(t.Context(), q)
 		require.NoError(t, err)
+		assert.Equal(t, []string{"carol", "dave", "alice"}, usernames(page))
+		require.NotEmpty(t, page.NextCursor)
+
+		q.Cursor = page.NextCursor
+		page, err = s.Members(t.Context(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob"}, usernames(page))
+		assert.Empty(t, page.NextCursor)
+	})
 
-		members, err := s.Members(t.Context())
+	t.Run("should page with offset", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+
+		page, err := s.Members(t.Context(), MemberQuery{Limit: 3, Page: 2, Sort: SortUsername})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dave"}, usernames(page))
+	})
+
+	t.Run("should filter and count", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+
+		page, err := s.Members(t.Context(), MemberQuery{
+			Limit:          1,
+			Sort:           SortLastName,
+			NamePrefix:     "Al",
+			RegisteredFrom: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			WithTotal:      true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"carol"}, usernames(page))
+		assert.Equal(t, int64(2), *page.Total)
+	})
+
+	t.Run("should escape like wildcards", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+
+		page, err := s.Members(t.Context(), MemberQuery{Limit: 10, Sort: SortUsername, NamePrefix: "Al_"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"carol"}, usernames(page))
+	})
+
+	t.Run("should filter birthday range", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+
+		page, err := s.Members(t.Context(), MemberQuery{
+			Limit:        10,
+			Sort:         SortUsername,
+			BirthdayFrom: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			BirthdayTo:   time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alice", "dave"}, usernames(page))
+	})
+
+	t.Run("should include the whole last registration day", func(t *testing.T) {
+		s := setupStorage(t)
+		midday := Member{Username: "erin", RegisterDate: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)}
+		require.NoError(t, s.Create(t.Context(), midday, newChange("erin", 1, ActionCreate)))
+
+		page, err := s.Members(t.Context(), MemberQuery{
+			Limit:          10,
+			Sort:           SortUsername,
+			RegisteredFrom: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			RegisteredTo:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"erin"}, usernames(page))
+
+		page, err = s.Members(t.Context(), MemberQuery{
+			Limit:        10,
+			Sort:         SortUsername,
+			RegisteredTo: time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, usernames(page))
+	})
+
+	t.Run("should hide deleted members unless included", func(t *testing.T) {
+		s := setupStorage(t)
+		seed(t, s)
+		bob := Member{Username: "bob", Version: 1, DeletedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+		require.NoError(t, s.Remove(t.Context(), bob, newChange("bob", 2, ActionDelete)))
+
+		page, err := s.Members(t.Context(), MemberQuery{Limit: 10, Sort: SortUsername, WithTotal: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alice", "carol", "dave"}, usernames(page))
+		assert.Equal(t, int64(3), *page.Total)
+
+		page, err = s.Members(t.Context(), MemberQuery{Limit: 10, Sort: SortUsername, IncludeDeleted: true})
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(members))
+		assert.Equal(t, []string{"alice", "bob", "carol", "dave"}, usernames(page))
+		assert.True(t, page.Members[1].Deleted())
+	})
+
+	t.Run("should reject unknown sort", func(t *testing.T) {
+		s := setupStorage(t)
+
+		_, err := s.Members(t.Context(), MemberQuery{Limit: 10, Sort: "password"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+	})
+}
+
+func TestStorageStream(t *testing.T) {
+	insert := func(t *testing.T, s *storage, username, firstName string, deletedAt any) {
+		t.Helper()
+		_, err := s.db.ExecContext(t.Context(),
+			"INSERT INTO member (username, first_name, last_name, birthday, register_date, deleted_at) VALUES (?, ?, ?, ?, ?, ?)",
+			username, firstName, "Last", "2000-01-01T00:00:00Z", "2025-01-01T00:00:00Z", deletedAt)
+		require.NoError(t, err)
+	}
+
+	t.Run("should stream filtered members in order", func(t *testing.T) {
+		s := setupStorage(t)
+		insert(t, s, "alice", "Alice", nil)
+		insert(t, s, "bob", "Bob", nil)
+		insert(t, s, "anna", "Anna", nil)
+		insert(t, s, "adam", "Adam", "2025-02-01T00:00:00Z")
+
+		var names []string
+		for m, err := range s.Stream(t.Context(), MemberQuery{Sort: SortFirstName, Desc: true, NamePrefix: "A", Limit: 1}) {
+			require.NoError(t, err)
+			names = append(names, m.Username)
+		}
+		assert.Equal(t, []string{"anna", "alice"}, names)
+	})
+
+	t.Run("should stop when the caller stops", func(t *testing.T) {
+		s := setupStorage(t)
+		insert(t, s, "alice", "Alice", nil)
+		insert(t, s, "bob", "Bob", nil)
+
+		for range s.Stream(t.Context(), MemberQuery{Sort: SortUsername}) {
+			break
+		}
+		_, found, err := s.Member(t.Context(), "bob")
+		require.NoError(t, err, "connection released")
+		assert.True(t, found)
+	})
+
+	t.Run("should reject unknown sort", func(t *testing.T) {
+		s := setupStorage(t)
+		for _, err := range s.Stream(t.Context(), MemberQuery{Sort: "password"}) {
+			assert.ErrorIs(t, err, ErrorInvalidQuery)
+		}
 	})
 }
 
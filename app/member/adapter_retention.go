@@ -0,0 +1,60 @@
+package member
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+)
+
+// retentionHook purges members soft deleted longer than retention ago, once at start and then
+// every interval until the hook stops.
+func retentionHook(svc Servicer, retention, interval time.Duration) lifecycle.Hook {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	return lifecycle.Hook{
+		Name: "member-retention",
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go runRetention(ctx, svc, retention, interval, done)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func runRetention(ctx context.Context, svc Servicer, retention, interval time.Duration, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := svc.PurgeDeleted(ctx, retention)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.ErrorContext(ctx, "purge deleted members fail", "err", err.Error())
+		case n > 0:
+			slog.InfoContext(ctx, "purged deleted members", "count", n, "retention", retention.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package member
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionHook(t *testing.T) {
+	t.Run("should purge at start and on every tick until stopped", func(t *testing.T) {
+		purged := make(chan struct{}, 10)
+		svc := newMockServicer(t)
+		svc.EXPECT().PurgeDeleted(mock.Anything, 24*time.Hour).
+			RunAndReturn(func(context.Context, time.Duration) (int64, error) {
+				purged <- struct{}{}
+				return 1, nil
+			})
+
+		hook := retentionHook(svc, 24*time.Hour, 5*time.Millisecond)
+		require.NoError(t, hook.OnStart(t.Context()))
+
+		for range 2 {
+			select {
+			case <-purged:
+			case <-time.After(time.Second):
+				t.Fatal("purge was not run")
+			}
+		}
+		assert.NoError(t, hook.OnStop(t.Context()))
+	})
+
+	t.Run("should keep running after an error", func(t *testing.T) {
+		calls := make(chan struct{}, 10)
+		svc := newMockServicer(t)
+		svc.EXPECT().PurgeDeleted(mock.Anything, time.Hour).
+			RunAndReturn(func(context.Context, time.Duration) (int64, error) {
+				calls <- struct{}{}
+				return 0, errors.New("db err")
+			})
+
+		hook := retentionHook(svc, time.Hour, 5*time.Millisecond)
+		require.NoError(t, hook.OnStart(t.Context()))
+
+		for range 2 {
+			select {
+			case <-calls:
+			case <-time.After(time.Second):
+				t.Fatal("purge was not retried")
+			}
+		}
+		assert.NoError(t, hook.OnStop(t.Context()))
+	})
+}
@@ -0,0 +1,157 @@
+package member
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+)
+
+type tracingService struct {
+	next Servicer
+}
+
+func NewTracingService(next Servicer) *tracingService {
+	return &tracingService{next: next}
+}
+
+func (s *tracingService) Members(ctx context.Context, query MemberQuery) (MemberPage, error) {
+	ctx, span := trace.Start(ctx, "member.Members", trace.WithAttributes(
+		slog.Int("member.query.limit", query.Limit),
+		slog.String("member.query.sort", string(query.Sort)),
+		slog.Bool("member.query.cursor", query.Cursor != ""),
+	))
+	defer span.End()
+
+	page, err := s.next.Members(ctx, query)
+	span.SetAttributes(slog.Int("member.count", len(page.Members)))
+	span.RecordError(err)
+	return page, err
+}
+
+func (s *tracingService) Member(ctx context.Context, username string) (Member, error) {
+	ctx, span := trace.Start(ctx, "member.Member", trace.WithAttributes(slog.String("member.username", username)))
+	defer span.End()
+
+	member, err := s.next.Member(ctx, username)
+	span.RecordError(err)
+	return member, err
+}
+
+func (s *tracingService) Create(ctx context.Context, member Member) error {
+	ctx, span := trace.Start(ctx, "member.Create", trace.WithAttributes(slog.String("member.username", member.Username)))
+	defer span.End()
+
+	err := s.next.Create(ctx, member)
+	span.RecordError(err)
+	return err
+}
+
+func (s *tracingService) Remove(ctx context.Context, username string, version int64) error {
+	ctx, span := trace.Start(ctx, "member.Remove", trace.WithAttributes(
+		slog.String("member.username", username),
+		slog.Int64("member.version", version),
+	))
+	defer span.End()
+
+	err := s.next.Remove(ctx, username, version)
+	span.RecordError(err)
+	return err
+}
+
+func (s *tracingService) Update(ctx context.Context, username string, version int64, member Member) (Member, error) {
+	ctx, span := trace.Start(ctx, "member.Update", trace.WithAttributes(
+		slog.String("member.username", username),
+		slog.Int64("member.version", version),
+	))
+	defer span.End()
+
+	member, err := s.next.Update(ctx, username, version, member)
+	span.RecordError(err)
+	return member, err
+}
+
+func (s *tracingService) Patch(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error) {
+	ctx, span := trace.Start(ctx, "member.Patch", trace.WithAttributes(
+		slog.String("member.username", username),
+		slog.Int64("member.version", version),
+	))
+	defer span.End()
+
+	member, err := s.next.Patch(ctx, username, version, patch)
+	span.RecordError(err)
+	return member, err
+}
+
+func (s *tracingService) Restore(ctx context.Context, username string, version int64) (Member, error) {
+	ctx, span := trace.Start(ctx, "member.Restore", trace.WithAttributes(
+		slog.String("member.username", username),
+		slog.Int64("member.version", version),
+	))
+	defer span.End()
+
+	member, err := s.next.Restore(ctx, username, version)
+	span.RecordError(err)
+	return member, err
+}
+
+func (s *tracingService) Purge(ctx context.Context, username string) error {
+	ctx, span := trace.Start(ctx, "member.Purge", trace.WithAttributes(slog.String("member.username", username)))
+	defer span.End()
+
+	err := s.next.Purge(ctx, username)
+	span.RecordError(err)
+	return err
+}
+
+func (s *tracingService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := trace.Start(ctx, "member.PurgeDeleted", trace.WithAttributes(slog.Duration("member.retention", retention)))
+	defer span.End()
+
+	n, err := s.next.PurgeDeleted(ctx, retention)
+	span.SetAttributes(slog.Int64("member.purged", n))
+	span.RecordError(err)
+	return n, err
+}
+
+func (s *tracingService) History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error) {
+	ctx, span := trace.Start(ctx, "member.History", trace.WithAttributes(
+		slog.String("member.username", username),
+		slog.Int("member.query.limit", query.Limit),
+		slog.Bool("member.query.cursor", query.Cursor != ""),
+	))
+	defer span.End()
+
+	page, err := s.next.History(ctx, username, query)
+	span.SetAttributes(slog.Int("member.count", len(page.Changes)))
+	span.RecordError(err)
+	return page, err
+}
+
+func (s *tracingService) Import(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error) {
+	ctx, span := trace.Start(ctx, "member.Import", trace.WithAttributes(slog.String("member.import.mode", string(mode))))
+	defer span.End()
+
+	report, err := s.next.Import(ctx, rows, mode)
+	span.SetAttributes(
+		slog.Int("member.import.accepted", report.Accepted),
+		slog.Int("member.import.rejected", report.Rejected),
+		slog.Int("member.import.imported", report.Imported),
+	)
+	span.RecordError(err)
+	return report, err
+}
+
+// Export only spans the setup, the storage spans the streamed query.
+func (s *tracingService) Export(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error) {
+	ctx, span := trace.Start(ctx, "member.Export", trace.WithAttributes(
+		slog.String("member.query.sort", string(query.Sort)),
+	))
+	defer span.End()
+
+	members, err := s.next.Export(ctx, query)
+	span.RecordError(err)
+	return members, err
+}
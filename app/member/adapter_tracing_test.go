@@ -0,0 +1,123 @@
+package member
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+)
+
+func TestTracingService(t *testing.T) {
+	member, _ := newFixture()
+
+	t.Run("should forward members", func(t *testing.T) {
+		svc := newMockServicer(t)
+		page := MemberPage{Members: []Member{member}}
+		svc.EXPECT().Members(mock.Anything, MemberQuery{Limit: 10}).Return(page, nil)
+
+		got, err := NewTracingService(svc).Members(contextBackground(), MemberQuery{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, page, got)
+	})
+
+	t.Run("should forward member", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Member(mock.Anything, "john").Return(member, nil)
+
+		got, err := NewTracingService(svc).Member(contextBackground(), "john")
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("should forward create error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Create(mock.Anything, member).Return(ErrorDuplicate)
+
+		err := NewTracingService(svc).Create(contextBackground(), member)
+		assert.ErrorIs(t, err, ErrorDuplicate)
+	})
+
+	t.Run("should forward update", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Update(mock.Anything, "john", int64(1), member).Return(member, nil)
+
+		got, err := NewTracingService(svc).Update(contextBackground(), "john", 1, member)
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("should forward patch", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Patch(mock.Anything, "john", int64(0), mock.Anything).Return(member, nil)
+
+		got, err := NewTracingService(svc).Patch(contextBackground(), "john", 0, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("should forward restore", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Restore(mock.Anything, "john", int64(2)).Return(member, nil)
+
+		got, err := NewTracingService(svc).Restore(contextBackground(), "john", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("should forward purge", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Purge(mock.Anything, "john").Return(nil)
+
+		assert.NoError(t, NewTracingService(svc).Purge(contextBackground(), "john"))
+	})
+
+	t.Run("should forward purge deleted", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().PurgeDeleted(mock.Anything, time.Hour).Return(2, nil)
+
+		n, err := NewTracingService(svc).PurgeDeleted(contextBackground(), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("should forward history", func(t *testing.T) {
+		page := HistoryPage{Changes: []Change{{Username: "john", Version: 1, Action: ActionCreate}}}
+		svc := newMockServicer(t)
+		svc.EXPECT().History(mock.Anything, "john", HistoryQuery{Limit: 5}).Return(page, nil)
+
+		got, err := NewTracingService(svc).History(contextBackground(), "john", HistoryQuery{Limit: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, page, got)
+	})
+
+	t.Run("should forward import", func(t *testing.T) {
+		report := ImportReport{Mode: ImportDryRun, Accepted: 1}
+		svc := newMockServicer(t)
+		svc.EXPECT().Import(mock.Anything, mock.Anything, ImportDryRun).Return(report, nil)
+
+		got, err := NewTracingService(svc).Import(contextBackground(), importRows(), ImportDryRun)
+		assert.NoError(t, err)
+		assert.Equal(t, report, got)
+	})
+
+	t.Run("should forward export", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Export(mock.Anything, MemberQuery{}).Return(func(yield func(Member, error) bool) { yield(member, nil) }, nil)
+
+		members, err := NewTracingService(svc).Export(contextBackground(), MemberQuery{})
+		assert.NoError(t, err)
+		for m := range members {
+			assert.Equal(t, member, m)
+		}
+	})
+
+	t.Run("should forward remove error", func(t *testing.T) {
+		svc := newMockServicer(t)
+		svc.EXPECT().Remove(mock.Anything, "john", int64(0)).Return(errors.New("db err"))
+
+		err := NewTracingService(svc).Remove(contextBackground(), "john", 0)
+		assert.ErrorContains(t, err, "db err")
+	})
+}
@@ -0,0 +1,63 @@
+package member
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgePolicy bounds a member's age in whole calendar years. MaxYears 0 has no upper bound and a nil
+// Location reads today's date in UTC.
+type AgePolicy struct {
+	MinYears int
+	MaxYears int
+	Location *time.Location
+}
+
+// AgeError reports an age outside the policy, it wraps ErrorMinAge or ErrorMaxAge.
+type AgeError struct {
+	Err      error
+	Age      int
+	MinYears int
+	MaxYears int
+}
+
+func (e *AgeError) Error() string {
+	return fmt.Sprintf("%v: age %d, allowed %d to %d", e.Err, e.Age, e.MinYears, e.MaxYears)
+}
+
+func (e *AgeError) Unwrap() error {
+	return e.Err
+}
+
+// Age counts the birthdays passed on the date of at in the policy location. The birthday is a
+// calendar date, so its own location is kept; a 29 February birthday passes on 1 March in common
+// years.
+func (p AgePolicy) Age(birthday, at time.Time) int {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	by, bm, bd := birthday.Date()
+	y, m, d := at.In(loc).Date()
+
+	age := y - by
+	if m < bm || (m == bm && d < bd) {
+		age--
+	}
+	return age
+}
+
+// Check returns an *AgeError when the age on at is outside the policy.
+func (p AgePolicy) Check(birthday, at time.Time) error {
+	age := p.Age(birthday, at)
+	var err error
+	switch {
+	case age < p.MinYears:
+		err = ErrorMinAge
+	case p.MaxYears > 0 && age > p.MaxYears:
+		err = ErrorMaxAge
+	default:
+		return nil
+	}
+	return &AgeError{Err: err, Age: age, MinYears: p.MinYears, MaxYears: p.MaxYears}
+}
@@ -0,0 +1,51 @@
+package member
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgePolicy(t *testing.T) {
+	policy := AgePolicy{MinYears: 15, MaxYears: 60}
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("should count calendar years", func(t *testing.T) {
+		birthday := date(2010, 6, 15)
+		assert.Equal(t, 14, policy.Age(birthday, date(2025, 6, 14)))
+		assert.Equal(t, 15, policy.Age(birthday, date(2025, 6, 15)))
+		assert.Equal(t, 15, policy.Age(birthday, date(2026, 6, 14)))
+	})
+
+	t.Run("should pass a leap day birthday on 1 March", func(t *testing.T) {
+		birthday := date(2008, 2, 29)
+		assert.Equal(t, 16, policy.Age(birthday, date(2025, 2, 28)))
+		assert.Equal(t, 17, policy.Age(birthday, date(2025, 3, 1)))
+		assert.Equal(t, 16, policy.Age(birthday, date(2024, 2, 29)))
+	})
+
+	t.Run("should read today in the policy location", func(t *testing.T) {
+		bangkok := time.FixedZone("ICT", 7*60*60)
+		birthday := date(2010, 6, 15)
+		at := time.Date(2025, 6, 14, 20, 0, 0, 0, time.UTC) // 15 June in Bangkok
+
+		assert.Equal(t, 14, policy.Age(birthday, at))
+		assert.Equal(t, 15, AgePolicy{Location: bangkok}.Age(birthday, at))
+	})
+
+	t.Run("should check the bounds", func(t *testing.T) {
+		now := date(2025, 1, 1)
+		assert.NoError(t, policy.Check(date(2010, 1, 1), now))
+		assert.NoError(t, policy.Check(date(1964, 1, 2), now))
+
+		err := policy.Check(date(2010, 1, 2), now)
+		assert.ErrorIs(t, err, ErrorMinAge)
+		assert.Equal(t, &AgeError{Err: ErrorMinAge, Age: 14, MinYears: 15, MaxYears: 60}, err)
+
+		assert.ErrorIs(t, policy.Check(date(1964, 1, 1), now), ErrorMaxAge)
+		assert.NoError(t, AgePolicy{MinYears: 15}.Check(date(1900, 1, 1), now), "no upper bound")
+	})
+}
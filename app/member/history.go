@@ -0,0 +1,124 @@
+package member
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+)
+
+// Action is the kind of write recorded in the member history.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// FieldDeletedAt only shows up in history, it changes on delete and restore.
+const FieldDeletedAt Field = "deletedAt"
+
+// FieldChange holds the value of a field before and after a write, nil when the field was unset.
+type FieldChange struct {
+	Field  Field `json:"field"`
+	Before any   `json:"before"`
+	After  any   `json:"after"`
+}
+
+// Change is one entry of the audit trail, Version is the member version the write produced.
+type Change struct {
+	Username  string        `json:"username"`
+	Version   int64         `json:"version"`
+	Action    Action        `json:"action"`
+	Actor     string        `json:"actor"`
+	TraceID   string        `json:"traceId,omitempty"`
+	Diff      []FieldChange `json:"diff"`
+	ChangedAt time.Time     `json:"changedAt"`
+}
+
+// Diff lists the fields that differ between before and after with both values.
+func Diff(before, after Member) []FieldChange {
+	diff := []FieldChange{}
+	fields := ChangedFields(before, after)
+	if !before.DeletedAt.Equal(after.DeletedAt) {
+		fields = append(fields, FieldDeletedAt)
+	}
+	for _, f := range fields {
+		diff = append(diff, FieldChange{Field: f, Before: historyValue(before, f), After: historyValue(after, f)})
+	}
+	return diff
+}
+
+// historyValue returns the field as it reads back from the stored diff, times are RFC 3339 strings.
+func historyValue(m Member, f Field) any {
+	var t time.Time
+	switch f {
+	case FieldFirstName:
+		return orNil(m.FirstName)
+	case FieldLastName:
+		return orNil(m.LastName)
+	case FieldBirthday:
+		t = m.Birthday
+	case FieldRegisterDate:
+		t = m.RegisterDate
+	case FieldDeletedAt:
+		t = m.DeletedAt
+	}
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func orNil(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// HistoryQuery selects one page of a member's history, newest first. Cursor continues after the
+// last change of the previous page.
+type HistoryQuery struct {
+	Limit  int
+	Cursor string
+}
+
+// HistoryPage has an empty NextCursor on the last page.
+type HistoryPage struct {
+	Changes    []Change
+	NextCursor string
+}
+
+func (q HistoryQuery) normalize() (HistoryQuery, error) {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	q.Limit = min(q.Limit, MaxLimit)
+	if _, err := q.Before(); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// Before decodes the cursor into the version the page starts below, 0 means the first page.
+func (q HistoryQuery) Before() (int64, error) {
+	if q.Cursor == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return 0, ErrorInvalidQuery
+	}
+	version, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrorInvalidQuery
+	}
+	return version, nil
+}
+
+// NextCursor encodes the version of last so the next page starts right below it.
+func (q HistoryQuery) NextCursor(last Change) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last.Version, 10)))
+}
@@ -0,0 +1,64 @@
+package member
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	member, now := newFixture()
+	member.RegisterDate = now
+
+	t.Run("should list created fields", func(t *testing.T) {
+		assert.Equal(t, []FieldChange{
+			{Field: FieldFirstName, Before: nil, After: "John"},
+			{Field: FieldLastName, Before: nil, After: "Doe"},
+			{Field: FieldBirthday, Before: nil, After: "2000-01-01T00:00:00Z"},
+			{Field: FieldRegisterDate, Before: nil, After: "2025-01-01T00:00:00Z"},
+		}, Diff(Member{}, member))
+	})
+
+	t.Run("should list deleted at", func(t *testing.T) {
+		deleted := member
+		deleted.DeletedAt = now.In(time.FixedZone("ICT", 7*60*60))
+
+		assert.Equal(t, []FieldChange{
+			{Field: FieldDeletedAt, Before: nil, After: "2025-01-01T00:00:00Z"},
+		}, Diff(member, deleted))
+		assert.Equal(t, []FieldChange{
+			{Field: FieldDeletedAt, Before: "2025-01-01T00:00:00Z", After: nil},
+		}, Diff(deleted, member))
+	})
+
+	t.Run("should be empty without changes", func(t *testing.T) {
+		assert.Equal(t, []FieldChange{}, Diff(member, member))
+	})
+}
+
+func TestHistoryQuery(t *testing.T) {
+	t.Run("should apply defaults", func(t *testing.T) {
+		q, err := HistoryQuery{}.normalize()
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultLimit, q.Limit)
+
+		q, err = HistoryQuery{Limit: 1000}.normalize()
+		assert.NoError(t, err)
+		assert.Equal(t, MaxLimit, q.Limit)
+	})
+
+	t.Run("should round trip cursor", func(t *testing.T) {
+		q := HistoryQuery{}
+		before, err := HistoryQuery{Cursor: q.NextCursor(Change{Version: 7})}.Before()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), before)
+	})
+
+	t.Run("should reject invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"!", "YWJj", "MA"} {
+			_, err := HistoryQuery{Cursor: cursor}.normalize()
+			assert.ErrorIs(t, err, ErrorInvalidQuery, cursor)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package member
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	// ErrorInvalidImport means the upload itself cannot be read, e.g. a CSV without header.
+	ErrorInvalidImport = errors.New("invalid member import")
+	// ErrorInvalidRow means one line of an upload is not a valid member.
+	ErrorInvalidRow = errors.New("invalid import row")
+)
+
+// ImportMode decides what Import writes.
+type ImportMode string
+
+const (
+	// ImportAtomic writes every row, or none of them when one is rejected.
+	ImportAtomic ImportMode = "atomic"
+	// ImportBestEffort writes the accepted rows and skips the rejected ones.
+	ImportBestEffort ImportMode = "best-effort"
+	// ImportDryRun validates every row and writes nothing.
+	ImportDryRun ImportMode = "dry-run"
+)
+
+var ImportModes = []ImportMode{ImportAtomic, ImportBestEffort, ImportDryRun}
+
+// ImportRow is one decoded line of an upload, Err is set when the line is not a valid member.
+type ImportRow struct {
+	Line   int
+	Member Member
+	Err    error
+}
+
+// ImportResult is the outcome of one row, Err is nil when the row was accepted.
+type ImportResult struct {
+	Line     int
+	Username string
+	Err      error
+}
+
+// ImportReport lists every row, Imported counts the rows actually written.
+type ImportReport struct {
+	Mode     ImportMode
+	Accepted int
+	Rejected int
+	Imported int
+	Results  []ImportResult
+}
+
+func (r *ImportReport) add(line int, username string, err error) {
+	if err != nil {
+		r.Rejected++
+	} else {
+		r.Accepted++
+	}
+	r.Results = append(r.Results, ImportResult{Line: line, Username: username, Err: err})
+}
+
+func (m ImportMode) valid() bool {
+	return slices.Contains(ImportModes, m)
+}
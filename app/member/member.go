@@ -3,14 +3,22 @@ package member
 import (
 	"context"
 	"errors"
+	"iter"
 	"time"
 )
 
 var (
+	// ErrorMinAge and ErrorMaxAge come wrapped in an *AgeError.
 	ErrorMinAge         = errors.New("min age limit")
 	ErrorMaxAge         = errors.New("max age limit")
 	ErrorDuplicate      = errors.New("duplicate username")
 	ErrorMemberNotFound = errors.New("member not found")
+	ErrorInvalidPatch   = errors.New("invalid member patch")
+	// ErrorVersionMismatch means the member changed since the version the caller read.
+	ErrorVersionMismatch = errors.New("member version mismatch")
+	ErrorNotDeleted      = errors.New("member is not deleted")
+	// ErrorConflict means the write lost a deadlock or serialization race, it can be retried.
+	ErrorConflict = errors.New("concurrent member write")
 )
 
 type Member struct {
@@ -19,27 +27,113 @@ type Member struct {
 	LastName     string    `json:"lastName"`
 	Birthday     time.Time `json:"birthday"`
 	RegisterDate time.Time `json:"registerDate"`
+	// Version starts at 1 and grows on every write, it is exposed as the ETag.
+	Version int64 `json:"-"`
+	// DeletedAt is set while the member is soft deleted, such members are hidden until restored.
+	DeletedAt time.Time `json:"deletedAt,omitzero"`
 }
 
+func (m Member) Deleted() bool {
+	return !m.DeletedAt.IsZero()
+}
+
+// Field names a writable member field, Username is the key and never changes.
+type Field string
+
+const (
+	FieldFirstName    Field = "firstName"
+	FieldLastName     Field = "lastName"
+	FieldBirthday     Field = "birthday"
+	FieldRegisterDate Field = "registerDate"
+)
+
+// ChangedFields lists the fields that differ between before and after.
+func ChangedFields(before, after Member) []Field {
+	var fields []Field
+	if before.FirstName != after.FirstName {
+		fields = append(fields, FieldFirstName)
+	}
+	if before.LastName != after.LastName {
+		fields = append(fields, FieldLastName)
+	}
+	if !before.Birthday.Equal(after.Birthday) {
+		fields = append(fields, FieldBirthday)
+	}
+	if !before.RegisterDate.Equal(after.RegisterDate) {
+		fields = append(fields, FieldRegisterDate)
+	}
+	return fields
+}
+
+// PatchFunc returns the member with a patch applied, it must not change Username.
+type PatchFunc func(Member) (Member, error)
+
 //mockery:generate: true
 type Storager interface {
-	Members(ctx context.Context) ([]Member, error)
+	Members(ctx context.Context, query MemberQuery) (MemberPage, error)
+	// Stream yields every member matching the query filters without paging.
+	Stream(ctx context.Context, query MemberQuery) iter.Seq2[Member, error]
+	// Member also finds soft deleted members, callers check Deleted.
 	Member(ctx context.Context, username string) (Member, bool, error)
-	Create(ctx context.Context, member Member) error
-	Remove(ctx context.Context, username string) error
-	Update(ctx context.Context, member Member) error
+	// The writes below record change in the history within the same transaction.
+	Create(ctx context.Context, member Member, change Change) error
+	// CreateAll creates every member or none, changes[i] records members[i].
+	CreateAll(ctx context.Context, members []Member, changes []Change) error
+	// Update, Patch, Remove and Restore only write when the stored version equals member.Version
+	// and bump it.
+	Update(ctx context.Context, member Member, change Change) error
+	Patch(ctx context.Context, member Member, fields []Field, change Change) error
+	// Remove soft deletes the member at member.DeletedAt.
+	Remove(ctx context.Context, member Member, change Change) error
+	Restore(ctx context.Context, member Member, change Change) error
+	// Purge permanently deletes a soft deleted member along with its history.
+	Purge(ctx context.Context, username string) error
+	// PurgeDeleted permanently deletes members soft deleted before the given time.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error)
 }
 
 //mockery:generate: true
 type Servicer interface {
-	Members(ctx context.Context) ([]Member, error)
+	Members(ctx context.Context, query MemberQuery) (MemberPage, error)
 	Member(ctx context.Context, username string) (Member, error)
+	// Export streams the members Members would list across all pages, the query is read lazily
+	// while ranging.
+	Export(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error)
 	Create(ctx context.Context, member Member) error
-	Remove(ctx context.Context, username string) error
-	Update(ctx context.Context, username string, member Member) error
+	// Remove, Update and Patch fail with ErrorVersionMismatch when version is not 0 and differs
+	// from the stored one.
+	Remove(ctx context.Context, username string, version int64) error
+	Update(ctx context.Context, username string, version int64, member Member) (Member, error)
+	Patch(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error)
+	Restore(ctx context.Context, username string, version int64) (Member, error)
+	Purge(ctx context.Context, username string) error
+	// PurgeDeleted permanently deletes members soft deleted longer than retention ago.
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
+	// History also serves soft deleted members.
+	History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error)
+	// Import validates rows like Create and writes them according to mode. A row error rejects
+	// the row, an error yielded next to it aborts the import.
+	Import(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error)
 }
 
 //mockery:generate: true
 type Clock interface {
 	Now() time.Time
 }
+
+// Caller tells who made the request recorded in the history.
+//
+//mockery:generate: true
+type Caller interface {
+	Actor(ctx context.Context) string
+	TraceID(ctx context.Context) string
+}
+
+// Transactor runs fn as one unit of work, storages called with the ctx it passes join the
+// transaction.
+//
+//mockery:generate: true
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
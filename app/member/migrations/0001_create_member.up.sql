@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS member (
+	username VARCHAR(64) NOT NULL PRIMARY KEY,
+	first_name VARCHAR(255) NOT NULL,
+	last_name VARCHAR(255) NOT NULL,
+	birthday DATETIME NOT NULL,
+	register_date DATETIME NOT NULL
+)
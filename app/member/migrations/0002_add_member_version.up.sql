@@ -0,0 +1 @@
+ALTER TABLE member ADD COLUMN version BIGINT NOT NULL DEFAULT 1
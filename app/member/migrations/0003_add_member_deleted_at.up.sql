@@ -0,0 +1 @@
+ALTER TABLE member ADD COLUMN deleted_at DATETIME NULL
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS member_history (
+	username VARCHAR(64) NOT NULL,
+	version BIGINT NOT NULL,
+	action VARCHAR(16) NOT NULL,
+	actor VARCHAR(255) NOT NULL,
+	trace_id VARCHAR(255) NOT NULL,
+	diff TEXT NOT NULL,
+	changed_at DATETIME NOT NULL,
+	PRIMARY KEY (username, version)
+)
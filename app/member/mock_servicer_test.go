@@ -6,6 +6,8 @@ package member
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -94,6 +96,216 @@ func (_c *mockServicer_Create_Call) RunAndReturn(run func(ctx context.Context, m
 	return _c
 }
 
+// Export provides a mock function for the type mockServicer
+func (_mock *mockServicer) Export(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 iter.Seq2[Member, error]
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) (iter.Seq2[Member, error], error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) iter.Seq2[Member, error]); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		r0 = ret.Get(0).(iter.Seq2[Member, error])
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, MemberQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type mockServicer_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query MemberQuery
+func (_e *mockServicer_Expecter) Export(ctx interface{}, query interface{}) *mockServicer_Export_Call {
+	return &mockServicer_Export_Call{Call: _e.mock.On("Export", ctx, query)}
+}
+
+func (_c *mockServicer_Export_Call) Run(run func(ctx context.Context, query MemberQuery)) *mockServicer_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 MemberQuery
+		if args[1] != nil {
+			arg1 = args[1].(MemberQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_Export_Call) Return(v iter.Seq2[Member, error], err error) *mockServicer_Export_Call {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *mockServicer_Export_Call) RunAndReturn(run func(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error)) *mockServicer_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// History provides a mock function for the type mockServicer
+func (_mock *mockServicer) History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error) {
+	ret := _mock.Called(ctx, username, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 HistoryPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, HistoryQuery) (HistoryPage, error)); ok {
+		return returnFunc(ctx, username, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, HistoryQuery) HistoryPage); ok {
+		r0 = returnFunc(ctx, username, query)
+	} else {
+		r0 = ret.Get(0).(HistoryPage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, HistoryQuery) error); ok {
+		r1 = returnFunc(ctx, username, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type mockServicer_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - query HistoryQuery
+func (_e *mockServicer_Expecter) History(ctx interface{}, username interface{}, query interface{}) *mockServicer_History_Call {
+	return &mockServicer_History_Call{Call: _e.mock.On("History", ctx, username, query)}
+}
+
+func (_c *mockServicer_History_Call) Run(run func(ctx context.Context, username string, query HistoryQuery)) *mockServicer_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 HistoryQuery
+		if args[2] != nil {
+			arg2 = args[2].(HistoryQuery)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_History_Call) Return(historyPage HistoryPage, err error) *mockServicer_History_Call {
+	_c.Call.Return(historyPage, err)
+	return _c
+}
+
+func (_c *mockServicer_History_Call) RunAndReturn(run func(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error)) *mockServicer_History_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function for the type mockServicer
+func (_mock *mockServicer) Import(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error) {
+	ret := _mock.Called(ctx, rows, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 ImportReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, iter.Seq2[ImportRow, error], ImportMode) (ImportReport, error)); ok {
+		return returnFunc(ctx, rows, mode)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, iter.Seq2[ImportRow, error], ImportMode) ImportReport); ok {
+		r0 = returnFunc(ctx, rows, mode)
+	} else {
+		r0 = ret.Get(0).(ImportReport)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, iter.Seq2[ImportRow, error], ImportMode) error); ok {
+		r1 = returnFunc(ctx, rows, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type mockServicer_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rows iter.Seq2[ImportRow, error]
+//   - mode ImportMode
+func (_e *mockServicer_Expecter) Import(ctx interface{}, rows interface{}, mode interface{}) *mockServicer_Import_Call {
+	return &mockServicer_Import_Call{Call: _e.mock.On("Import", ctx, rows, mode)}
+}
+
+func (_c *mockServicer_Import_Call) Run(run func(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode)) *mockServicer_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 iter.Seq2[ImportRow, error]
+		if args[1] != nil {
+			arg1 = args[1].(iter.Seq2[ImportRow, error])
+		}
+		var arg2 ImportMode
+		if args[2] != nil {
+			arg2 = args[2].(ImportMode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_Import_Call) Return(importReport ImportReport, err error) *mockServicer_Import_Call {
+	_c.Call.Return(importReport, err)
+	return _c
+}
+
+func (_c *mockServicer_Import_Call) RunAndReturn(run func(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error)) *mockServicer_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Member provides a mock function for the type mockServicer
 func (_mock *mockServicer) Member(ctx context.Context, username string) (Member, error) {
 	ret := _mock.Called(ctx, username)
@@ -161,27 +373,25 @@ func (_c *mockServicer_Member_Call) RunAndReturn(run func(ctx context.Context, u
 }
 
 // Members provides a mock function for the type mockServicer
-func (_mock *mockServicer) Members(ctx context.Context) ([]Member, error) {
-	ret := _mock.Called(ctx)
+func (_mock *mockServicer) Members(ctx context.Context, query MemberQuery) (MemberPage, error) {
+	ret := _mock.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Members")
 	}
 
-	var r0 []Member
+	var r0 MemberPage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]Member, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) (MemberPage, error)); ok {
+		return returnFunc(ctx, query)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []Member); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) MemberPage); ok {
+		r0 = returnFunc(ctx, query)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Member)
-		}
+		r0 = ret.Get(0).(MemberPage)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, MemberQuery) error); ok {
+		r1 = returnFunc(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -195,39 +405,123 @@ type mockServicer_Members_Call struct {
 
 // Members is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *mockServicer_Expecter) Members(ctx interface{}) *mockServicer_Members_Call {
-	return &mockServicer_Members_Call{Call: _e.mock.On("Members", ctx)}
+//   - query MemberQuery
+func (_e *mockServicer_Expecter) Members(ctx interface{}, query interface{}) *mockServicer_Members_Call {
+	return &mockServicer_Members_Call{Call: _e.mock.On("Members", ctx, query)}
 }
 
-func (_c *mockServicer_Members_Call) Run(run func(ctx context.Context)) *mockServicer_Members_Call {
+func (_c *mockServicer_Members_Call) Run(run func(ctx context.Context, query MemberQuery)) *mockServicer_Members_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 MemberQuery
+		if args[1] != nil {
+			arg1 = args[1].(MemberQuery)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *mockServicer_Members_Call) Return(members []Member, err error) *mockServicer_Members_Call {
-	_c.Call.Return(members, err)
+func (_c *mockServicer_Members_Call) Return(memberPage MemberPage, err error) *mockServicer_Members_Call {
+	_c.Call.Return(memberPage, err)
 	return _c
 }
 
-func (_c *mockServicer_Members_Call) RunAndReturn(run func(ctx context.Context) ([]Member, error)) *mockServicer_Members_Call {
+func (_c *mockServicer_Members_Call) RunAndReturn(run func(ctx context.Context, query MemberQuery) (MemberPage, error)) *mockServicer_Members_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Remove provides a mock function for the type mockServicer
-func (_mock *mockServicer) Remove(ctx context.Context, username string) error {
+// Patch provides a mock function for the type mockServicer
+func (_mock *mockServicer) Patch(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error) {
+	ret := _mock.Called(ctx, username, version, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 Member
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, PatchFunc) (Member, error)); ok {
+		return returnFunc(ctx, username, version, patch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, PatchFunc) Member); ok {
+		r0 = returnFunc(ctx, username, version, patch)
+	} else {
+		r0 = ret.Get(0).(Member)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, PatchFunc) error); ok {
+		r1 = returnFunc(ctx, username, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type mockServicer_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - version int64
+//   - patch PatchFunc
+func (_e *mockServicer_Expecter) Patch(ctx interface{}, username interface{}, version interface{}, patch interface{}) *mockServicer_Patch_Call {
+	return &mockServicer_Patch_Call{Call: _e.mock.On("Patch", ctx, username, version, patch)}
+}
+
+func (_c *mockServicer_Patch_Call) Run(run func(ctx context.Context, username string, version int64, patch PatchFunc)) *mockServicer_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 PatchFunc
+		if args[3] != nil {
+			arg3 = args[3].(PatchFunc)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_Patch_Call) Return(member Member, err error) *mockServicer_Patch_Call {
+	_c.Call.Return(member, err)
+	return _c
+}
+
+func (_c *mockServicer_Patch_Call) RunAndReturn(run func(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error)) *mockServicer_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function for the type mockServicer
+func (_mock *mockServicer) Purge(ctx context.Context, username string) error {
 	ret := _mock.Called(ctx, username)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Remove")
+		panic("no return value specified for Purge")
 	}
 
 	var r0 error
@@ -239,6 +533,129 @@ func (_mock *mockServicer) Remove(ctx context.Context, username string) error {
 	return r0
 }
 
+// mockServicer_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type mockServicer_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *mockServicer_Expecter) Purge(ctx interface{}, username interface{}) *mockServicer_Purge_Call {
+	return &mockServicer_Purge_Call{Call: _e.mock.On("Purge", ctx, username)}
+}
+
+func (_c *mockServicer_Purge_Call) Run(run func(ctx context.Context, username string)) *mockServicer_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_Purge_Call) Return(err error) *mockServicer_Purge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockServicer_Purge_Call) RunAndReturn(run func(ctx context.Context, username string) error) *mockServicer_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeleted provides a mock function for the type mockServicer
+func (_mock *mockServicer) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	ret := _mock.Called(ctx, retention)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) (int64, error)); ok {
+		return returnFunc(ctx, retention)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration) int64); ok {
+		r0 = returnFunc(ctx, retention)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = returnFunc(ctx, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_PurgeDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeleted'
+type mockServicer_PurgeDeleted_Call struct {
+	*mock.Call
+}
+
+// PurgeDeleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - retention time.Duration
+func (_e *mockServicer_Expecter) PurgeDeleted(ctx interface{}, retention interface{}) *mockServicer_PurgeDeleted_Call {
+	return &mockServicer_PurgeDeleted_Call{Call: _e.mock.On("PurgeDeleted", ctx, retention)}
+}
+
+func (_c *mockServicer_PurgeDeleted_Call) Run(run func(ctx context.Context, retention time.Duration)) *mockServicer_PurgeDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_PurgeDeleted_Call) Return(n int64, err error) *mockServicer_PurgeDeleted_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockServicer_PurgeDeleted_Call) RunAndReturn(run func(ctx context.Context, retention time.Duration) (int64, error)) *mockServicer_PurgeDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Remove provides a mock function for the type mockServicer
+func (_mock *mockServicer) Remove(ctx context.Context, username string, version int64) error {
+	ret := _mock.Called(ctx, username, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = returnFunc(ctx, username, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
 // mockServicer_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
 type mockServicer_Remove_Call struct {
 	*mock.Call
@@ -247,11 +664,12 @@ type mockServicer_Remove_Call struct {
 // Remove is a helper method to define mock.On call
 //   - ctx context.Context
 //   - username string
-func (_e *mockServicer_Expecter) Remove(ctx interface{}, username interface{}) *mockServicer_Remove_Call {
-	return &mockServicer_Remove_Call{Call: _e.mock.On("Remove", ctx, username)}
+//   - version int64
+func (_e *mockServicer_Expecter) Remove(ctx interface{}, username interface{}, version interface{}) *mockServicer_Remove_Call {
+	return &mockServicer_Remove_Call{Call: _e.mock.On("Remove", ctx, username, version)}
 }
 
-func (_c *mockServicer_Remove_Call) Run(run func(ctx context.Context, username string)) *mockServicer_Remove_Call {
+func (_c *mockServicer_Remove_Call) Run(run func(ctx context.Context, username string, version int64)) *mockServicer_Remove_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -261,9 +679,14 @@ func (_c *mockServicer_Remove_Call) Run(run func(ctx context.Context, username s
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -274,26 +697,107 @@ func (_c *mockServicer_Remove_Call) Return(err error) *mockServicer_Remove_Call
 	return _c
 }
 
-func (_c *mockServicer_Remove_Call) RunAndReturn(run func(ctx context.Context, username string) error) *mockServicer_Remove_Call {
+func (_c *mockServicer_Remove_Call) RunAndReturn(run func(ctx context.Context, username string, version int64) error) *mockServicer_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function for the type mockServicer
+func (_mock *mockServicer) Restore(ctx context.Context, username string, version int64) (Member, error) {
+	ret := _mock.Called(ctx, username, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 Member
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (Member, error)); ok {
+		return returnFunc(ctx, username, version)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) Member); ok {
+		r0 = returnFunc(ctx, username, version)
+	} else {
+		r0 = ret.Get(0).(Member)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, username, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockServicer_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type mockServicer_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - version int64
+func (_e *mockServicer_Expecter) Restore(ctx interface{}, username interface{}, version interface{}) *mockServicer_Restore_Call {
+	return &mockServicer_Restore_Call{Call: _e.mock.On("Restore", ctx, username, version)}
+}
+
+func (_c *mockServicer_Restore_Call) Run(run func(ctx context.Context, username string, version int64)) *mockServicer_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockServicer_Restore_Call) Return(member Member, err error) *mockServicer_Restore_Call {
+	_c.Call.Return(member, err)
+	return _c
+}
+
+func (_c *mockServicer_Restore_Call) RunAndReturn(run func(ctx context.Context, username string, version int64) (Member, error)) *mockServicer_Restore_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Update provides a mock function for the type mockServicer
-func (_mock *mockServicer) Update(ctx context.Context, username string, member Member) error {
-	ret := _mock.Called(ctx, username, member)
+func (_mock *mockServicer) Update(ctx context.Context, username string, version int64, member Member) (Member, error) {
+	ret := _mock.Called(ctx, username, version, member)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, Member) error); ok {
-		r0 = returnFunc(ctx, username, member)
+	var r0 Member
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, Member) (Member, error)); ok {
+		return returnFunc(ctx, username, version, member)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, Member) Member); ok {
+		r0 = returnFunc(ctx, username, version, member)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(Member)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, Member) error); ok {
+		r1 = returnFunc(ctx, username, version, member)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // mockServicer_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
@@ -304,12 +808,13 @@ type mockServicer_Update_Call struct {
 // Update is a helper method to define mock.On call
 //   - ctx context.Context
 //   - username string
+//   - version int64
 //   - member Member
-func (_e *mockServicer_Expecter) Update(ctx interface{}, username interface{}, member interface{}) *mockServicer_Update_Call {
-	return &mockServicer_Update_Call{Call: _e.mock.On("Update", ctx, username, member)}
+func (_e *mockServicer_Expecter) Update(ctx interface{}, username interface{}, version interface{}, member interface{}) *mockServicer_Update_Call {
+	return &mockServicer_Update_Call{Call: _e.mock.On("Update", ctx, username, version, member)}
 }
 
-func (_c *mockServicer_Update_Call) Run(run func(ctx context.Context, username string, member Member)) *mockServicer_Update_Call {
+func (_c *mockServicer_Update_Call) Run(run func(ctx context.Context, username string, version int64, member Member)) *mockServicer_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -319,25 +824,30 @@ func (_c *mockServicer_Update_Call) Run(run func(ctx context.Context, username s
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 Member
+		var arg2 int64
 		if args[2] != nil {
-			arg2 = args[2].(Member)
+			arg2 = args[2].(int64)
+		}
+		var arg3 Member
+		if args[3] != nil {
+			arg3 = args[3].(Member)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockServicer_Update_Call) Return(err error) *mockServicer_Update_Call {
-	_c.Call.Return(err)
+func (_c *mockServicer_Update_Call) Return(member Member, err error) *mockServicer_Update_Call {
+	_c.Call.Return(member, err)
 	return _c
 }
 
-func (_c *mockServicer_Update_Call) RunAndReturn(run func(ctx context.Context, username string, member Member) error) *mockServicer_Update_Call {
+func (_c *mockServicer_Update_Call) RunAndReturn(run func(ctx context.Context, username string, version int64, member Member) (Member, error)) *mockServicer_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,8 @@ package member
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -38,16 +40,16 @@ func (_m *mockStorager) EXPECT() *mockStorager_Expecter {
 }
 
 // Create provides a mock function for the type mockStorager
-func (_mock *mockStorager) Create(ctx context.Context, member Member) error {
-	ret := _mock.Called(ctx, member)
+func (_mock *mockStorager) Create(ctx context.Context, member Member, change Change) error {
+	ret := _mock.Called(ctx, member, change)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, Member) error); ok {
-		r0 = returnFunc(ctx, member)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Member, Change) error); ok {
+		r0 = returnFunc(ctx, member, change)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,11 +64,12 @@ type mockStorager_Create_Call struct {
 // Create is a helper method to define mock.On call
 //   - ctx context.Context
 //   - member Member
-func (_e *mockStorager_Expecter) Create(ctx interface{}, member interface{}) *mockStorager_Create_Call {
-	return &mockStorager_Create_Call{Call: _e.mock.On("Create", ctx, member)}
+//   - change Change
+func (_e *mockStorager_Expecter) Create(ctx interface{}, member interface{}, change interface{}) *mockStorager_Create_Call {
+	return &mockStorager_Create_Call{Call: _e.mock.On("Create", ctx, member, change)}
 }
 
-func (_c *mockStorager_Create_Call) Run(run func(ctx context.Context, member Member)) *mockStorager_Create_Call {
+func (_c *mockStorager_Create_Call) Run(run func(ctx context.Context, member Member, change Change)) *mockStorager_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -76,9 +79,14 @@ func (_c *mockStorager_Create_Call) Run(run func(ctx context.Context, member Mem
 		if args[1] != nil {
 			arg1 = args[1].(Member)
 		}
+		var arg2 Change
+		if args[2] != nil {
+			arg2 = args[2].(Change)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -89,7 +97,142 @@ func (_c *mockStorager_Create_Call) Return(err error) *mockStorager_Create_Call
 	return _c
 }
 
-func (_c *mockStorager_Create_Call) RunAndReturn(run func(ctx context.Context, member Member) error) *mockStorager_Create_Call {
+func (_c *mockStorager_Create_Call) RunAndReturn(run func(ctx context.Context, member Member, change Change) error) *mockStorager_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAll provides a mock function for the type mockStorager
+func (_mock *mockStorager) CreateAll(ctx context.Context, members []Member, changes []Change) error {
+	ret := _mock.Called(ctx, members, changes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAll")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []Member, []Change) error); ok {
+		r0 = returnFunc(ctx, members, changes)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStorager_CreateAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAll'
+type mockStorager_CreateAll_Call struct {
+	*mock.Call
+}
+
+// CreateAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - members []Member
+//   - changes []Change
+func (_e *mockStorager_Expecter) CreateAll(ctx interface{}, members interface{}, changes interface{}) *mockStorager_CreateAll_Call {
+	return &mockStorager_CreateAll_Call{Call: _e.mock.On("CreateAll", ctx, members, changes)}
+}
+
+func (_c *mockStorager_CreateAll_Call) Run(run func(ctx context.Context, members []Member, changes []Change)) *mockStorager_CreateAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []Member
+		if args[1] != nil {
+			arg1 = args[1].([]Member)
+		}
+		var arg2 []Change
+		if args[2] != nil {
+			arg2 = args[2].([]Change)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_CreateAll_Call) Return(err error) *mockStorager_CreateAll_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStorager_CreateAll_Call) RunAndReturn(run func(ctx context.Context, members []Member, changes []Change) error) *mockStorager_CreateAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// History provides a mock function for the type mockStorager
+func (_mock *mockStorager) History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error) {
+	ret := _mock.Called(ctx, username, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 HistoryPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, HistoryQuery) (HistoryPage, error)); ok {
+		return returnFunc(ctx, username, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, HistoryQuery) HistoryPage); ok {
+		r0 = returnFunc(ctx, username, query)
+	} else {
+		r0 = ret.Get(0).(HistoryPage)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, HistoryQuery) error); ok {
+		r1 = returnFunc(ctx, username, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockStorager_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type mockStorager_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - query HistoryQuery
+func (_e *mockStorager_Expecter) History(ctx interface{}, username interface{}, query interface{}) *mockStorager_History_Call {
+	return &mockStorager_History_Call{Call: _e.mock.On("History", ctx, username, query)}
+}
+
+func (_c *mockStorager_History_Call) Run(run func(ctx context.Context, username string, query HistoryQuery)) *mockStorager_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 HistoryQuery
+		if args[2] != nil {
+			arg2 = args[2].(HistoryQuery)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_History_Call) Return(historyPage HistoryPage, err error) *mockStorager_History_Call {
+	_c.Call.Return(historyPage, err)
+	return _c
+}
+
+func (_c *mockStorager_History_Call) RunAndReturn(run func(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error)) *mockStorager_History_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -167,27 +310,25 @@ func (_c *mockStorager_Member_Call) RunAndReturn(run func(ctx context.Context, u
 }
 
 // Members provides a mock function for the type mockStorager
-func (_mock *mockStorager) Members(ctx context.Context) ([]Member, error) {
-	ret := _mock.Called(ctx)
+func (_mock *mockStorager) Members(ctx context.Context, query MemberQuery) (MemberPage, error) {
+	ret := _mock.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Members")
 	}
 
-	var r0 []Member
+	var r0 MemberPage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]Member, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) (MemberPage, error)); ok {
+		return returnFunc(ctx, query)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []Member); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) MemberPage); ok {
+		r0 = returnFunc(ctx, query)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Member)
-		}
+		r0 = ret.Get(0).(MemberPage)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, MemberQuery) error); ok {
+		r1 = returnFunc(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -201,39 +342,114 @@ type mockStorager_Members_Call struct {
 
 // Members is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *mockStorager_Expecter) Members(ctx interface{}) *mockStorager_Members_Call {
-	return &mockStorager_Members_Call{Call: _e.mock.On("Members", ctx)}
+//   - query MemberQuery
+func (_e *mockStorager_Expecter) Members(ctx interface{}, query interface{}) *mockStorager_Members_Call {
+	return &mockStorager_Members_Call{Call: _e.mock.On("Members", ctx, query)}
 }
 
-func (_c *mockStorager_Members_Call) Run(run func(ctx context.Context)) *mockStorager_Members_Call {
+func (_c *mockStorager_Members_Call) Run(run func(ctx context.Context, query MemberQuery)) *mockStorager_Members_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 MemberQuery
+		if args[1] != nil {
+			arg1 = args[1].(MemberQuery)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *mockStorager_Members_Call) Return(members []Member, err error) *mockStorager_Members_Call {
-	_c.Call.Return(members, err)
+func (_c *mockStorager_Members_Call) Return(memberPage MemberPage, err error) *mockStorager_Members_Call {
+	_c.Call.Return(memberPage, err)
 	return _c
 }
 
-func (_c *mockStorager_Members_Call) RunAndReturn(run func(ctx context.Context) ([]Member, error)) *mockStorager_Members_Call {
+func (_c *mockStorager_Members_Call) RunAndReturn(run func(ctx context.Context, query MemberQuery) (MemberPage, error)) *mockStorager_Members_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Remove provides a mock function for the type mockStorager
-func (_mock *mockStorager) Remove(ctx context.Context, username string) error {
+// Patch provides a mock function for the type mockStorager
+func (_mock *mockStorager) Patch(ctx context.Context, member Member, fields []Field, change Change) error {
+	ret := _mock.Called(ctx, member, fields, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Member, []Field, Change) error); ok {
+		r0 = returnFunc(ctx, member, fields, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStorager_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type mockStorager_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - member Member
+//   - fields []Field
+//   - change Change
+func (_e *mockStorager_Expecter) Patch(ctx interface{}, member interface{}, fields interface{}, change interface{}) *mockStorager_Patch_Call {
+	return &mockStorager_Patch_Call{Call: _e.mock.On("Patch", ctx, member, fields, change)}
+}
+
+func (_c *mockStorager_Patch_Call) Run(run func(ctx context.Context, member Member, fields []Field, change Change)) *mockStorager_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 Member
+		if args[1] != nil {
+			arg1 = args[1].(Member)
+		}
+		var arg2 []Field
+		if args[2] != nil {
+			arg2 = args[2].([]Field)
+		}
+		var arg3 Change
+		if args[3] != nil {
+			arg3 = args[3].(Change)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_Patch_Call) Return(err error) *mockStorager_Patch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStorager_Patch_Call) RunAndReturn(run func(ctx context.Context, member Member, fields []Field, change Change) error) *mockStorager_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function for the type mockStorager
+func (_mock *mockStorager) Purge(ctx context.Context, username string) error {
 	ret := _mock.Called(ctx, username)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Remove")
+		panic("no return value specified for Purge")
 	}
 
 	var r0 error
@@ -245,19 +461,19 @@ func (_mock *mockStorager) Remove(ctx context.Context, username string) error {
 	return r0
 }
 
-// mockStorager_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
-type mockStorager_Remove_Call struct {
+// mockStorager_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type mockStorager_Purge_Call struct {
 	*mock.Call
 }
 
-// Remove is a helper method to define mock.On call
+// Purge is a helper method to define mock.On call
 //   - ctx context.Context
 //   - username string
-func (_e *mockStorager_Expecter) Remove(ctx interface{}, username interface{}) *mockStorager_Remove_Call {
-	return &mockStorager_Remove_Call{Call: _e.mock.On("Remove", ctx, username)}
+func (_e *mockStorager_Expecter) Purge(ctx interface{}, username interface{}) *mockStorager_Purge_Call {
+	return &mockStorager_Purge_Call{Call: _e.mock.On("Purge", ctx, username)}
 }
 
-func (_c *mockStorager_Remove_Call) Run(run func(ctx context.Context, username string)) *mockStorager_Remove_Call {
+func (_c *mockStorager_Purge_Call) Run(run func(ctx context.Context, username string)) *mockStorager_Purge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -275,27 +491,276 @@ func (_c *mockStorager_Remove_Call) Run(run func(ctx context.Context, username s
 	return _c
 }
 
+func (_c *mockStorager_Purge_Call) Return(err error) *mockStorager_Purge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStorager_Purge_Call) RunAndReturn(run func(ctx context.Context, username string) error) *mockStorager_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeleted provides a mock function for the type mockStorager
+func (_mock *mockStorager) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ret := _mock.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, before)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockStorager_PurgeDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeleted'
+type mockStorager_PurgeDeleted_Call struct {
+	*mock.Call
+}
+
+// PurgeDeleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *mockStorager_Expecter) PurgeDeleted(ctx interface{}, before interface{}) *mockStorager_PurgeDeleted_Call {
+	return &mockStorager_PurgeDeleted_Call{Call: _e.mock.On("PurgeDeleted", ctx, before)}
+}
+
+func (_c *mockStorager_PurgeDeleted_Call) Run(run func(ctx context.Context, before time.Time)) *mockStorager_PurgeDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_PurgeDeleted_Call) Return(n int64, err error) *mockStorager_PurgeDeleted_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockStorager_PurgeDeleted_Call) RunAndReturn(run func(ctx context.Context, before time.Time) (int64, error)) *mockStorager_PurgeDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Remove provides a mock function for the type mockStorager
+func (_mock *mockStorager) Remove(ctx context.Context, member Member, change Change) error {
+	ret := _mock.Called(ctx, member, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Member, Change) error); ok {
+		r0 = returnFunc(ctx, member, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStorager_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
+type mockStorager_Remove_Call struct {
+	*mock.Call
+}
+
+// Remove is a helper method to define mock.On call
+//   - ctx context.Context
+//   - member Member
+//   - change Change
+func (_e *mockStorager_Expecter) Remove(ctx interface{}, member interface{}, change interface{}) *mockStorager_Remove_Call {
+	return &mockStorager_Remove_Call{Call: _e.mock.On("Remove", ctx, member, change)}
+}
+
+func (_c *mockStorager_Remove_Call) Run(run func(ctx context.Context, member Member, change Change)) *mockStorager_Remove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 Member
+		if args[1] != nil {
+			arg1 = args[1].(Member)
+		}
+		var arg2 Change
+		if args[2] != nil {
+			arg2 = args[2].(Change)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
 func (_c *mockStorager_Remove_Call) Return(err error) *mockStorager_Remove_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mockStorager_Remove_Call) RunAndReturn(run func(ctx context.Context, username string) error) *mockStorager_Remove_Call {
+func (_c *mockStorager_Remove_Call) RunAndReturn(run func(ctx context.Context, member Member, change Change) error) *mockStorager_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function for the type mockStorager
+func (_mock *mockStorager) Restore(ctx context.Context, member Member, change Change) error {
+	ret := _mock.Called(ctx, member, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Member, Change) error); ok {
+		r0 = returnFunc(ctx, member, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockStorager_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type mockStorager_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - member Member
+//   - change Change
+func (_e *mockStorager_Expecter) Restore(ctx interface{}, member interface{}, change interface{}) *mockStorager_Restore_Call {
+	return &mockStorager_Restore_Call{Call: _e.mock.On("Restore", ctx, member, change)}
+}
+
+func (_c *mockStorager_Restore_Call) Run(run func(ctx context.Context, member Member, change Change)) *mockStorager_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 Member
+		if args[1] != nil {
+			arg1 = args[1].(Member)
+		}
+		var arg2 Change
+		if args[2] != nil {
+			arg2 = args[2].(Change)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_Restore_Call) Return(err error) *mockStorager_Restore_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockStorager_Restore_Call) RunAndReturn(run func(ctx context.Context, member Member, change Change) error) *mockStorager_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stream provides a mock function for the type mockStorager
+func (_mock *mockStorager) Stream(ctx context.Context, query MemberQuery) iter.Seq2[Member, error] {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 iter.Seq2[Member, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, MemberQuery) iter.Seq2[Member, error]); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		r0 = ret.Get(0).(iter.Seq2[Member, error])
+	}
+	return r0
+}
+
+// mockStorager_Stream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stream'
+type mockStorager_Stream_Call struct {
+	*mock.Call
+}
+
+// Stream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query MemberQuery
+func (_e *mockStorager_Expecter) Stream(ctx interface{}, query interface{}) *mockStorager_Stream_Call {
+	return &mockStorager_Stream_Call{Call: _e.mock.On("Stream", ctx, query)}
+}
+
+func (_c *mockStorager_Stream_Call) Run(run func(ctx context.Context, query MemberQuery)) *mockStorager_Stream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 MemberQuery
+		if args[1] != nil {
+			arg1 = args[1].(MemberQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockStorager_Stream_Call) Return(v iter.Seq2[Member, error]) *mockStorager_Stream_Call {
+	_c.Call.Return(v)
+	return _c
+}
+
+func (_c *mockStorager_Stream_Call) RunAndReturn(run func(ctx context.Context, query MemberQuery) iter.Seq2[Member, error]) *mockStorager_Stream_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Update provides a mock function for the type mockStorager
-func (_mock *mockStorager) Update(ctx context.Context, member Member) error {
-	ret := _mock.Called(ctx, member)
+func (_mock *mockStorager) Update(ctx context.Context, member Member, change Change) error {
+	ret := _mock.Called(ctx, member, change)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, Member) error); ok {
-		r0 = returnFunc(ctx, member)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, Member, Change) error); ok {
+		r0 = returnFunc(ctx, member, change)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -310,11 +775,12 @@ type mockStorager_Update_Call struct {
 // Update is a helper method to define mock.On call
 //   - ctx context.Context
 //   - member Member
-func (_e *mockStorager_Expecter) Update(ctx interface{}, member interface{}) *mockStorager_Update_Call {
-	return &mockStorager_Update_Call{Call: _e.mock.On("Update", ctx, member)}
+//   - change Change
+func (_e *mockStorager_Expecter) Update(ctx interface{}, member interface{}, change interface{}) *mockStorager_Update_Call {
+	return &mockStorager_Update_Call{Call: _e.mock.On("Update", ctx, member, change)}
 }
 
-func (_c *mockStorager_Update_Call) Run(run func(ctx context.Context, member Member)) *mockStorager_Update_Call {
+func (_c *mockStorager_Update_Call) Run(run func(ctx context.Context, member Member, change Change)) *mockStorager_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -324,9 +790,14 @@ func (_c *mockStorager_Update_Call) Run(run func(ctx context.Context, member Mem
 		if args[1] != nil {
 			arg1 = args[1].(Member)
 		}
+		var arg2 Change
+		if args[2] != nil {
+			arg2 = args[2].(Change)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -337,7 +808,7 @@ func (_c *mockStorager_Update_Call) Return(err error) *mockStorager_Update_Call
 	return _c
 }
 
-func (_c *mockStorager_Update_Call) RunAndReturn(run func(ctx context.Context, member Member) error) *mockStorager_Update_Call {
+func (_c *mockStorager_Update_Call) RunAndReturn(run func(ctx context.Context, member Member, change Change) error) *mockStorager_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
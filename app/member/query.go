@@ -0,0 +1,126 @@
+package member
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrorInvalidQuery = errors.New("invalid member query")
+
+type SortField string
+
+const (
+	SortUsername     SortField = "username"
+	SortFirstName    SortField = "firstName"
+	SortLastName     SortField = "lastName"
+	SortBirthday     SortField = "birthday"
+	SortRegisterDate SortField = "registerDate"
+)
+
+// SortFields is the whitelist of fields members can be sorted by.
+var SortFields = []SortField{SortUsername, SortFirstName, SortLastName, SortBirthday, SortRegisterDate}
+
+// MemberQuery selects one page of members. Cursor continues after the last member of the previous
+// page, Page is 1-based offset paging and is only used without a cursor. Time bounds are dates, both
+// ends inclusive of the whole day, and zero bounds are open.
+type MemberQuery struct {
+	Limit  int
+	Cursor string
+	Page   int
+	Sort   SortField
+	Desc   bool
+
+	NamePrefix     string
+	BirthdayFrom   time.Time
+	BirthdayTo     time.Time
+	RegisteredFrom time.Time
+	RegisteredTo   time.Time
+
+	WithTotal      bool
+	IncludeDeleted bool
+}
+
+// MemberPage has an empty NextCursor on the last page, Total is only set when requested.
+type MemberPage struct {
+	Members    []Member
+	NextCursor string
+	Total      *int64
+}
+
+// normalize applies defaults and rejects unknown sort fields or a cursor from another sort order.
+func (q MemberQuery) normalize() (MemberQuery, error) {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	q.Limit = min(q.Limit, MaxLimit)
+	if q.Sort == "" {
+		q.Sort = SortUsername
+	}
+	if !slices.Contains(SortFields, q.Sort) || q.Page < 0 || (q.Page > 0 && q.Cursor != "") {
+		return q, ErrorInvalidQuery
+	}
+	if _, err := q.After(); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// Cursor is the decoded position after which the next page starts.
+type Cursor struct {
+	Sort     SortField `json:"s"`
+	Desc     bool      `json:"d,omitempty"`
+	Value    string    `json:"v"`
+	Username string    `json:"u"`
+}
+
+// After decodes the query cursor, nil means the first page.
+func (q MemberQuery) After() (*Cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, ErrorInvalidQuery
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != q.Sort || c.Desc != q.Desc {
+		return nil, ErrorInvalidQuery
+	}
+	return &c, nil
+}
+
+// NextCursor encodes the position of last so the next page starts right after it.
+func (q MemberQuery) NextCursor(last Member) string {
+	c := Cursor{Sort: q.Sort, Desc: q.Desc, Value: sortValue(q.Sort, last), Username: last.Username}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sortValue(sort SortField, m Member) string {
+	switch sort {
+	case SortFirstName:
+		return m.FirstName
+	case SortLastName:
+		return m.LastName
+	case SortBirthday:
+		return m.Birthday.Format(time.RFC3339Nano)
+	case SortRegisterDate:
+		return m.RegisterDate.Format(time.RFC3339Nano)
+	default:
+		return m.Username
+	}
+}
+
+// IsTime reports whether the cursor value of the field is an RFC 3339 time.
+func (s SortField) IsTime() bool {
+	return s == SortBirthday || s == SortRegisterDate
+}
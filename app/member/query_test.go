@@ -0,0 +1,36 @@
+package member
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberQueryCursor(t *testing.T) {
+	t.Run("should return nil without cursor", func(t *testing.T) {
+		after, err := MemberQuery{}.After()
+		assert.NoError(t, err)
+		assert.Nil(t, after)
+	})
+
+	t.Run("should round trip cursor", func(t *testing.T) {
+		q := MemberQuery{Sort: SortBirthday, Desc: true}
+		birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+		q.Cursor = q.NextCursor(Member{Username: "john", Birthday: birthday})
+
+		after, err := q.After()
+		require.NoError(t, err)
+		assert.Equal(t, &Cursor{Sort: SortBirthday, Desc: true, Value: "2000-01-02T00:00:00Z", Username: "john"}, after)
+	})
+
+	t.Run("should reject cursor of another order", func(t *testing.T) {
+		q := MemberQuery{Sort: SortUsername}
+		q.Cursor = q.NextCursor(Member{Username: "john"})
+		q.Desc = true
+
+		_, err := q.After()
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+	})
+}
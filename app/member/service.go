@@ -1,13 +1,46 @@
 package member
 
+import (
+	"context"
+	"time"
+)
+
 type service struct {
 	storage Storager
+	tx      Transactor
 	clock   Clock
+	caller  Caller
+	age     AgePolicy
 }
 
-func NewService(storage Storager, clock Clock) *service {
+func NewService(storage Storager, tx Transactor, clock Clock, caller Caller, age AgePolicy) *service {
 	return &service{
 		storage: storage,
+		tx:      tx,
 		clock:   clock,
+		caller:  caller,
+		age:     age,
+	}
+}
+
+// checkBirthday applies the age policy when a write changes the birthday, so members who outgrew
+// the policy can still edit the rest of their profile.
+func (s *service) checkBirthday(current, m Member) error {
+	if m.Birthday.Equal(current.Birthday) {
+		return nil
+	}
+	return s.age.Check(m.Birthday, s.clock.Now())
+}
+
+// change records the write turning before into after, after.Version is the version it produces.
+func (s *service) change(ctx context.Context, action Action, before, after Member, at time.Time) Change {
+	return Change{
+		Username:  after.Username,
+		Version:   after.Version,
+		Action:    action,
+		Actor:     s.caller.Actor(ctx),
+		TraceID:   s.caller.TraceID(ctx),
+		Diff:      Diff(before, after),
+		ChangedAt: at,
 	}
 }
@@ -3,20 +3,18 @@ package member
 import (
 	"context"
 	"fmt"
-	"time"
 )
 
 func (s *service) Create(ctx context.Context, m Member) error {
-	m.RegisterDate = s.clock.Now()
+	now := s.clock.Now()
+	m.RegisterDate = now
+	m.Version = 1
 
-	age := m.RegisterDate.Sub(m.Birthday)
-	if age < 15*365*24*time.Hour {
-		return ErrorMinAge
-	}
-	if age > 60*365*24*time.Hour {
-		return ErrorMaxAge
+	if err := s.age.Check(m.Birthday, now); err != nil {
+		return err
 	}
 
+	// a plain read, there is no row to lock yet and the unique username rejects a racing create
 	_, exiting, err := s.storage.Member(ctx, m.Username)
 	if err != nil {
 		return fmt.Errorf("create member: %w", err)
@@ -25,5 +23,5 @@ func (s *service) Create(ctx context.Context, m Member) error {
 		return ErrorDuplicate
 	}
 
-	return s.storage.Create(ctx, m)
+	return s.storage.Create(ctx, m, s.change(ctx, ActionCreate, Member{}, m, now))
 }
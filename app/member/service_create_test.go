@@ -2,10 +2,12 @@ package member
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestServiceCreate(t *testing.T) {
@@ -13,12 +15,13 @@ func TestServiceCreate(t *testing.T) {
 		member, now := newFixture()
 		expected := member
 		expected.RegisterDate = now
+		expected.Version = 1
 
 		svc := newServiceWithMocks(t, func(c *mockClock) {
 			c.On("Now").Return(now)
 		}, func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
-			m.EXPECT().Create(contextBackground(), expected).Return(nil)
+			m.EXPECT().Create(contextBackground(), expected, recorded(ActionCreate, Member{}, expected, now)).Return(nil)
 		})
 
 		err := svc.Create(contextBackground(), member)
@@ -79,15 +82,31 @@ func TestServiceCreate(t *testing.T) {
 		member, now := newFixture()
 		expected := member
 		expected.RegisterDate = now
+		expected.Version = 1
 
 		svc := newServiceWithMocks(t, func(c *mockClock) {
 			c.On("Now").Return(now)
 		}, func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
-			m.EXPECT().Create(contextBackground(), expected).Return(errors.New("insert err"))
+			m.EXPECT().Create(contextBackground(), expected, recorded(ActionCreate, Member{}, expected, now)).Return(errors.New("insert err"))
 		})
 
 		err := svc.Create(contextBackground(), member)
 		assert.ErrorContains(t, err, "insert err")
 	})
+
+	t.Run("should check without a transaction and leave races to the unique username", func(t *testing.T) {
+		member, now := newFixture()
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+			m.EXPECT().Create(contextBackground(), mock.Anything, mock.Anything).Return(fmt.Errorf("%w: dup entry", ErrorDuplicate))
+		})
+		svc.tx = newMockTransactor(t)
+
+		err := svc.Create(contextBackground(), member)
+		assert.ErrorIs(t, err, ErrorDuplicate)
+	})
 }
@@ -0,0 +1,16 @@
+package member
+
+import (
+	"context"
+	"iter"
+)
+
+// Export ignores the paging fields of the query and keeps its filters and sort order.
+func (s *service) Export(ctx context.Context, query MemberQuery) (iter.Seq2[Member, error], error) {
+	query.Cursor, query.Page, query.WithTotal = "", 0, false
+	query, err := query.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.Stream(ctx, query), nil
+}
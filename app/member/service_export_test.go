@@ -0,0 +1,32 @@
+package member
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceExport(t *testing.T) {
+	t.Run("should drop paging and keep filters", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Stream(contextBackground(), MemberQuery{Limit: DefaultLimit, Sort: SortLastName, NamePrefix: "Jo"}).
+				Return(func(yield func(Member, error) bool) { yield(member, nil) })
+		})
+
+		members, err := svc.Export(contextBackground(), MemberQuery{Sort: SortLastName, NamePrefix: "Jo", Cursor: "abc", Page: 2, WithTotal: true})
+		assert.NoError(t, err)
+		for m, err := range members {
+			assert.NoError(t, err)
+			assert.Equal(t, member, m)
+		}
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), noStorage())
+
+		_, err := svc.Export(contextBackground(), MemberQuery{Sort: "password"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+	})
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 )
 
-func (s *service) Members(ctx context.Context) ([]Member, error) {
-	members, err := s.storage.Members(ctx)
+func (s *service) Members(ctx context.Context, query MemberQuery) (MemberPage, error) {
+	query, err := query.normalize()
 	if err != nil {
-		return nil, fmt.Errorf("get all member: %w", err)
+		return MemberPage{}, err
 	}
-	return members, nil
+
+	page, err := s.storage.Members(ctx, query)
+	if err != nil {
+		return MemberPage{}, fmt.Errorf("get all member: %w", err)
+	}
+	return page, nil
 }
@@ -10,23 +10,47 @@ import (
 func TestServiceMembers(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		member, _ := newFixture()
-		expected := []Member{member}
+		expected := MemberPage{Members: []Member{member}}
 
 		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
-			m.EXPECT().Members(contextBackground()).Return(expected, nil)
+			m.EXPECT().Members(contextBackground(), MemberQuery{Limit: DefaultLimit, Sort: SortUsername}).Return(expected, nil)
 		})
 
-		got, err := svc.Members(contextBackground())
+		got, err := svc.Members(contextBackground(), MemberQuery{})
 		assert.NoError(t, err)
 		assert.Equal(t, expected, got)
 	})
 
+	t.Run("should cap limit", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Members(contextBackground(), MemberQuery{Limit: MaxLimit, Sort: SortBirthday, Desc: true}).Return(MemberPage{}, nil)
+		})
+
+		_, err := svc.Members(contextBackground(), MemberQuery{Limit: 1000, Sort: SortBirthday, Desc: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), noStorage())
+
+		for _, q := range []MemberQuery{
+			{Sort: "password"},
+			{Page: -1},
+			{Page: 2, Cursor: MemberQuery{Sort: SortUsername}.NextCursor(Member{Username: "john"})},
+			{Cursor: "not-a-cursor"},
+			{Sort: SortBirthday, Cursor: MemberQuery{Sort: SortUsername}.NextCursor(Member{Username: "john"})},
+		} {
+			_, err := svc.Members(contextBackground(), q)
+			assert.ErrorIs(t, err, ErrorInvalidQuery)
+		}
+	})
+
 	t.Run("storage error", func(t *testing.T) {
 		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
-			m.EXPECT().Members(contextBackground()).Return(nil, errors.New("db err"))
+			m.EXPECT().Members(contextBackground(), MemberQuery{Limit: DefaultLimit, Sort: SortUsername}).Return(MemberPage{}, errors.New("db err"))
 		})
 
-		_, err := svc.Members(contextBackground())
+		_, err := svc.Members(contextBackground(), MemberQuery{})
 		assert.ErrorContains(t, err, "db err")
 	})
 }
@@ -6,12 +6,12 @@ import (
 )
 
 func (s *service) Member(ctx context.Context, username string) (Member, error) {
-	members, found, err := s.storage.Member(ctx, username)
+	member, found, err := s.storage.Member(ctx, username)
 	if err != nil {
 		return Member{}, fmt.Errorf("get member by username: %w", err)
 	}
-	if !found {
+	if !found || member.Deleted() {
 		return Member{}, ErrorMemberNotFound
 	}
-	return members, nil
+	return member, nil
 }
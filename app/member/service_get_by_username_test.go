@@ -20,6 +20,18 @@ func TestServiceMember(t *testing.T) {
 		assert.Equal(t, member, got)
 	})
 
+	t.Run("should hide deleted member", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Member(contextBackground(), "john")
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
@@ -0,0 +1,27 @@
+package member
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *service) History(ctx context.Context, username string, query HistoryQuery) (HistoryPage, error) {
+	query, err := query.normalize()
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	_, found, err := s.storage.Member(ctx, username)
+	if err != nil {
+		return HistoryPage{}, fmt.Errorf("member history: %w", err)
+	}
+	if !found {
+		return HistoryPage{}, ErrorMemberNotFound
+	}
+
+	page, err := s.storage.History(ctx, username, query)
+	if err != nil {
+		return HistoryPage{}, fmt.Errorf("member history: %w", err)
+	}
+	return page, nil
+}
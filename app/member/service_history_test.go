@@ -0,0 +1,53 @@
+package member
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+		page := HistoryPage{Changes: []Change{{Username: "john", Version: 1, Action: ActionCreate}}}
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().History(contextBackground(), "john", HistoryQuery{Limit: DefaultLimit}).Return(page, nil)
+		})
+
+		got, err := svc.History(contextBackground(), "john", HistoryQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, page, got)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), noStorage())
+
+		_, err := svc.History(contextBackground(), "john", HistoryQuery{Cursor: "bad"})
+		assert.ErrorIs(t, err, ErrorInvalidQuery)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
+		})
+
+		_, err := svc.History(contextBackground(), "unknown", HistoryQuery{})
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
+	})
+
+	t.Run("storage error on history", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().History(contextBackground(), "john", HistoryQuery{Limit: DefaultLimit}).Return(HistoryPage{}, errors.New("db err"))
+		})
+
+		_, err := svc.History(contextBackground(), "john", HistoryQuery{})
+		assert.ErrorContains(t, err, "db err")
+	})
+}
@@ -0,0 +1,69 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Import checks every row before an atomic write, while best-effort writes each row as it goes.
+func (s *service) Import(ctx context.Context, rows iter.Seq2[ImportRow, error], mode ImportMode) (ImportReport, error) {
+	if !mode.valid() {
+		return ImportReport{}, fmt.Errorf("%w: mode %q", ErrorInvalidImport, mode)
+	}
+
+	now := s.clock.Now()
+	report := ImportReport{Mode: mode, Results: []ImportResult{}}
+	seen := map[string]bool{}
+	var (
+		members []Member
+		changes []Change
+	)
+	for row, err := range rows {
+		if err != nil {
+			return ImportReport{}, fmt.Errorf("import members: %w", err)
+		}
+
+		m := row.Member
+		m.RegisterDate = now
+		m.Version = 1
+		err := row.Err
+		if err == nil {
+			err = s.age.Check(m.Birthday, now)
+		}
+		if err == nil && seen[m.Username] {
+			err = fmt.Errorf("%w: repeated in upload", ErrorDuplicate)
+		}
+		if err == nil {
+			_, exiting, lookupErr := s.storage.Member(ctx, m.Username)
+			if lookupErr != nil {
+				return ImportReport{}, fmt.Errorf("import members: %w", lookupErr)
+			}
+			if exiting {
+				err = ErrorDuplicate
+			}
+		}
+		if err == nil {
+			seen[m.Username] = true
+			change := s.change(ctx, ActionCreate, Member{}, m, now)
+			switch mode {
+			case ImportBestEffort:
+				if err = s.storage.Create(ctx, m, change); err == nil {
+					report.Imported++
+				}
+			case ImportAtomic:
+				members = append(members, m)
+				changes = append(changes, change)
+			}
+		}
+		report.add(row.Line, m.Username, err)
+	}
+
+	if mode == ImportAtomic && report.Rejected == 0 && len(members) > 0 {
+		if err := s.storage.CreateAll(ctx, members, changes); err != nil {
+			return ImportReport{}, fmt.Errorf("import members: %w", err)
+		}
+		report.Imported = len(members)
+	}
+	return report, nil
+}
@@ -0,0 +1,159 @@
+package member
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func importRows(rows ...ImportRow) iter.Seq2[ImportRow, error] {
+	return func(yield func(ImportRow, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestServiceImport(t *testing.T) {
+	john, now := newFixture()
+	jane := john
+	jane.Username = "jane"
+	young := john
+	young.Username = "young"
+	young.Birthday = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	created := func(m Member) Member {
+		m.RegisterDate = now
+		m.Version = 1
+		return m
+	}
+	withClock := func(c *mockClock) { c.On("Now").Return(now) }
+
+	t.Run("atomic writes every row at once", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+			m.EXPECT().Member(contextBackground(), "jane").Return(Member{}, false, nil)
+			m.EXPECT().CreateAll(contextBackground(),
+				[]Member{created(john), created(jane)},
+				[]Change{
+					recorded(ActionCreate, Member{}, created(john), now),
+					recorded(ActionCreate, Member{}, created(jane), now),
+				}).Return(nil)
+		})
+
+		report, err := svc.Import(contextBackground(), importRows(
+			ImportRow{Line: 2, Member: john},
+			ImportRow{Line: 3, Member: jane},
+		), ImportAtomic)
+		assert.NoError(t, err)
+		assert.Equal(t, ImportReport{
+			Mode:     ImportAtomic,
+			Accepted: 2,
+			Imported: 2,
+			Results:  []ImportResult{{Line: 2, Username: "john"}, {Line: 3, Username: "jane"}},
+		}, report)
+	})
+
+	t.Run("atomic writes nothing when a row is rejected", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+		})
+
+		report, err := svc.Import(contextBackground(), importRows(
+			ImportRow{Line: 2, Member: john},
+			ImportRow{Line: 3, Member: young},
+			ImportRow{Line: 4, Member: john},
+			ImportRow{Line: 5, Err: ErrorInvalidRow},
+		), ImportAtomic)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Accepted)
+		assert.Equal(t, 3, report.Rejected)
+		assert.Zero(t, report.Imported)
+		assert.ErrorIs(t, report.Results[1].Err, ErrorMinAge)
+		assert.ErrorIs(t, report.Results[2].Err, ErrorDuplicate)
+		assert.ErrorIs(t, report.Results[3].Err, ErrorInvalidRow)
+	})
+
+	t.Run("best effort writes accepted rows", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, true, nil)
+			m.EXPECT().Member(contextBackground(), "jane").Return(Member{}, false, nil)
+			m.EXPECT().Create(contextBackground(), created(jane), recorded(ActionCreate, Member{}, created(jane), now)).Return(nil)
+		})
+
+		report, err := svc.Import(contextBackground(), importRows(
+			ImportRow{Line: 2, Member: john},
+			ImportRow{Line: 3, Member: jane},
+		), ImportBestEffort)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Accepted)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Equal(t, 1, report.Imported)
+		assert.ErrorIs(t, report.Results[0].Err, ErrorDuplicate)
+	})
+
+	t.Run("best effort rejects rows the storage fails", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+			m.EXPECT().Create(contextBackground(), mock.Anything, mock.Anything).Return(errors.New("insert err"))
+		})
+
+		report, err := svc.Import(contextBackground(), importRows(ImportRow{Line: 2, Member: john}), ImportBestEffort)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Zero(t, report.Imported)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+		})
+
+		report, err := svc.Import(contextBackground(), importRows(ImportRow{Line: 2, Member: john}), ImportDryRun)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Accepted)
+		assert.Zero(t, report.Imported)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), noStorage())
+
+		_, err := svc.Import(contextBackground(), importRows(), "all")
+		assert.ErrorIs(t, err, ErrorInvalidImport)
+	})
+
+	t.Run("read error aborts", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, noStorage())
+
+		rows := func(yield func(ImportRow, error) bool) {
+			yield(ImportRow{}, ErrorInvalidImport)
+		}
+		_, err := svc.Import(contextBackground(), rows, ImportAtomic)
+		assert.ErrorIs(t, err, ErrorInvalidImport)
+	})
+
+	t.Run("storage error on member check aborts", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, errors.New("db err"))
+		})
+
+		_, err := svc.Import(contextBackground(), importRows(ImportRow{Line: 2, Member: john}), ImportDryRun)
+		assert.ErrorContains(t, err, "db err")
+	})
+
+	t.Run("storage error on create all", func(t *testing.T) {
+		svc := newServiceWithMocks(t, withClock, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, nil)
+			m.EXPECT().CreateAll(contextBackground(), mock.Anything, mock.Anything).Return(errors.New("tx err"))
+		})
+
+		_, err := svc.Import(contextBackground(), importRows(ImportRow{Line: 2, Member: john}), ImportAtomic)
+		assert.ErrorContains(t, err, "tx err")
+	})
+}
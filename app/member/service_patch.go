@@ -0,0 +1,51 @@
+package member
+
+import (
+	"context"
+	"fmt"
+)
+
+// Patch always writes against the version it read, so a concurrent write fails instead of being lost.
+func (s *service) Patch(ctx context.Context, username string, version int64, patch PatchFunc) (Member, error) {
+	var patched Member
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		current, found, err := s.storage.Member(ctx, username)
+		if err != nil {
+			return fmt.Errorf("patch member: %w", err)
+		}
+		if !found || current.Deleted() {
+			return ErrorMemberNotFound
+		}
+		if version != 0 && version != current.Version {
+			return ErrorVersionMismatch
+		}
+
+		m, err := patch(current)
+		if err != nil {
+			return err
+		}
+		m.Version = current.Version
+		if m.Username != current.Username {
+			return fmt.Errorf("%w: username cannot be changed", ErrorInvalidPatch)
+		}
+		if err := s.checkBirthday(current, m); err != nil {
+			return err
+		}
+
+		fields := ChangedFields(current, m)
+		if len(fields) == 0 {
+			patched = current
+			return nil
+		}
+		patched = m
+		patched.Version++
+		if err := s.storage.Patch(ctx, m, fields, s.change(ctx, ActionUpdate, current, patched, s.clock.Now())); err != nil {
+			return fmt.Errorf("patch member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Member{}, err
+	}
+	return patched, nil
+}
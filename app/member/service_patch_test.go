@@ -0,0 +1,155 @@
+package member
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+)
+
+func TestServicePatch(t *testing.T) {
+	member, now := newFixture()
+	member.RegisterDate = now
+	member.Version = 3
+
+	rename := func(m Member) (Member, error) {
+		m.FirstName = "Johnny"
+		return m, nil
+	}
+
+	t.Run("should write changed fields", func(t *testing.T) {
+		patched := member
+		patched.FirstName = "Johnny"
+		written := patched
+		written.Version = 4
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Patch(contextBackground(), patched, []Field{FieldFirstName}, recorded(ActionUpdate, member, written, now)).Return(nil)
+		})
+
+		got, err := svc.Patch(contextBackground(), "john", 0, rename)
+		assert.NoError(t, err)
+		assert.Equal(t, written, got)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 2, rename)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+	})
+
+	t.Run("should keep stored version when patch drops it", func(t *testing.T) {
+		patched := member
+		patched.FirstName = "Johnny"
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Patch(contextBackground(), patched, []Field{FieldFirstName}, mock.Anything).Return(nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 3, func(m Member) (Member, error) {
+			m.FirstName = "Johnny"
+			m.Version = 0
+			return m, nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should skip write when nothing changed", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		got, err := svc.Patch(contextBackground(), "john", 0, func(m Member) (Member, error) { return m, nil })
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "unknown", 0, rename)
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
+	})
+
+	t.Run("storage error on member check", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, errors.New("db err"))
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 0, rename)
+		assert.ErrorContains(t, err, "db err")
+	})
+
+	t.Run("should return patch error", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 0, func(Member) (Member, error) {
+			return Member{}, ErrorInvalidPatch
+		})
+		assert.ErrorIs(t, err, ErrorInvalidPatch)
+	})
+
+	t.Run("should reject username change", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 0, func(m Member) (Member, error) {
+			m.Username = "jane"
+			return m, nil
+		})
+		assert.ErrorIs(t, err, ErrorInvalidPatch)
+	})
+
+	t.Run("should validate age", func(t *testing.T) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 0, func(m Member) (Member, error) {
+			m.Birthday = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			return m, nil
+		})
+		assert.ErrorIs(t, err, ErrorMinAge)
+	})
+
+	t.Run("storage error on patch", func(t *testing.T) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Patch(contextBackground(), mock.Anything, []Field{FieldFirstName}, mock.Anything).Return(errors.New("patch err"))
+		})
+
+		_, err := svc.Patch(contextBackground(), "john", 0, rename)
+		assert.ErrorContains(t, err, "patch err")
+	})
+}
+
+func TestChangedFields(t *testing.T) {
+	member, now := newFixture()
+
+	after := member
+	after.LastName = "Smith"
+	after.RegisterDate = now
+
+	assert.Empty(t, ChangedFields(member, member))
+	assert.Equal(t, []Field{FieldLastName, FieldRegisterDate}, ChangedFields(member, after))
+}
@@ -0,0 +1,36 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Purge permanently deletes a member, it must be soft deleted first.
+func (s *service) Purge(ctx context.Context, username string) error {
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		current, found, err := s.storage.Member(ctx, username)
+		if err != nil {
+			return fmt.Errorf("purge member: %w", err)
+		}
+		if !found {
+			return ErrorMemberNotFound
+		}
+		if !current.Deleted() {
+			return ErrorNotDeleted
+		}
+
+		if err := s.storage.Purge(ctx, username); err != nil {
+			return fmt.Errorf("purge member: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *service) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	n, err := s.storage.PurgeDeleted(ctx, s.clock.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted members: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package member
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicePurge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Purge(contextBackground(), "john").Return(nil)
+		})
+
+		assert.NoError(t, svc.Purge(contextBackground(), "john"))
+	})
+
+	t.Run("should refuse a member that is not deleted", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		assert.ErrorIs(t, svc.Purge(contextBackground(), "john"), ErrorNotDeleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
+		})
+
+		assert.ErrorIs(t, svc.Purge(contextBackground(), "unknown"), ErrorMemberNotFound)
+	})
+
+	t.Run("storage error on purge", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Purge(contextBackground(), "john").Return(errors.New("purge err"))
+		})
+
+		assert.ErrorContains(t, svc.Purge(contextBackground(), "john"), "purge err")
+	})
+}
+
+func TestServicePurgeDeleted(t *testing.T) {
+	_, now := newFixture()
+
+	t.Run("should purge before retention cutoff", func(t *testing.T) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().PurgeDeleted(contextBackground(), now.Add(-48*time.Hour)).Return(2, nil)
+		})
+
+		n, err := svc.PurgeDeleted(contextBackground(), 48*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().PurgeDeleted(contextBackground(), now).Return(0, errors.New("purge err"))
+		})
+
+		_, err := svc.PurgeDeleted(contextBackground(), 0)
+		assert.ErrorContains(t, err, "purge err")
+	})
+}
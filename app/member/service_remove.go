@@ -5,18 +5,29 @@ import (
 	"fmt"
 )
 
-func (s *service) Remove(ctx context.Context, username string) error {
-	_, found, err := s.storage.Member(ctx, username)
-	if err != nil {
-		return fmt.Errorf("remove member: %w", err)
-	}
-	if !found {
-		return ErrorMemberNotFound
-	}
+func (s *service) Remove(ctx context.Context, username string, version int64) error {
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		current, found, err := s.storage.Member(ctx, username)
+		if err != nil {
+			return fmt.Errorf("remove member: %w", err)
+		}
+		if !found || current.Deleted() {
+			return ErrorMemberNotFound
+		}
+		if version != 0 && version != current.Version {
+			return ErrorVersionMismatch
+		}
 
-	err = s.storage.Remove(ctx, username)
-	if err != nil {
-		return fmt.Errorf("remove member: %w", err)
-	}
-	return err
+		now := s.clock.Now()
+		deleted := current
+		deleted.DeletedAt = now
+		deleted.Version++
+		change := s.change(ctx, ActionDelete, current, deleted, now)
+
+		current.DeletedAt = now
+		if err := s.storage.Remove(ctx, current, change); err != nil {
+			return fmt.Errorf("remove member: %w", err)
+		}
+		return nil
+	})
 }
@@ -5,19 +5,68 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestServiceRemove(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 3
+		removed := member
+		removed.DeletedAt = now
+		deleted := removed
+		deleted.Version = 4
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Remove(contextBackground(), removed, recorded(ActionDelete, member, deleted, now)).Return(nil)
+		})
+
+		err := svc.Remove(contextBackground(), "john", 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success with matching version", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 3
+		removed := member
+		removed.DeletedAt = now
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Remove(contextBackground(), removed, mock.Anything).Return(nil)
+		})
+
+		err := svc.Remove(contextBackground(), "john", 3)
+		assert.NoError(t, err)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
 		member, _ := newFixture()
+		member.Version = 3
 
 		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
-			m.EXPECT().Remove(contextBackground(), "john").Return(nil)
 		})
 
-		err := svc.Remove(contextBackground(), "john")
-		assert.NoError(t, err)
+		err := svc.Remove(contextBackground(), "john", 2)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+	})
+
+	t.Run("already deleted", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		err := svc.Remove(contextBackground(), "john", 0)
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -25,7 +74,7 @@ func TestServiceRemove(t *testing.T) {
 			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
 		})
 
-		err := svc.Remove(contextBackground(), "unknown")
+		err := svc.Remove(contextBackground(), "unknown", 0)
 		assert.ErrorIs(t, err, ErrorMemberNotFound)
 	})
 
@@ -34,19 +83,34 @@ func TestServiceRemove(t *testing.T) {
 			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, errors.New("db err"))
 		})
 
-		err := svc.Remove(contextBackground(), "john")
+		err := svc.Remove(contextBackground(), "john", 0)
 		assert.ErrorContains(t, err, "db err")
 	})
 
 	t.Run("storage error on remove", func(t *testing.T) {
-		member, _ := newFixture()
+		member, now := newFixture()
 
-		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
-			m.EXPECT().Remove(contextBackground(), "john").Return(errors.New("delete err"))
+			m.EXPECT().Remove(contextBackground(), mock.Anything, mock.Anything).Return(errors.New("delete err"))
 		})
 
-		err := svc.Remove(contextBackground(), "john")
+		err := svc.Remove(contextBackground(), "john", 0)
 		assert.ErrorContains(t, err, "delete err")
 	})
+	t.Run("should check and remove in one transaction", func(t *testing.T) {
+		member, now := newFixture()
+
+		svc := withTx(t, newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(txContext(), "john").Return(member, true, nil)
+			m.EXPECT().Remove(txContext(), mock.Anything, mock.Anything).Return(nil)
+		}), errors.New("commit err"))
+
+		err := svc.Remove(contextBackground(), "john", 0)
+		assert.EqualError(t, err, "commit err")
+	})
 }
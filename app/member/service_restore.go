@@ -0,0 +1,42 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Restore undoes a soft delete, restoring a member that is not deleted changes nothing.
+func (s *service) Restore(ctx context.Context, username string, version int64) (Member, error) {
+	var restored Member
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		current, found, err := s.storage.Member(ctx, username)
+		if err != nil {
+			return fmt.Errorf("restore member: %w", err)
+		}
+		if !found {
+			return ErrorMemberNotFound
+		}
+		if version != 0 && version != current.Version {
+			return ErrorVersionMismatch
+		}
+		if !current.Deleted() {
+			restored = current
+			return nil
+		}
+
+		restored = current
+		restored.DeletedAt = time.Time{}
+		restored.Version++
+		change := s.change(ctx, ActionRestore, current, restored, s.clock.Now())
+
+		if err := s.storage.Restore(ctx, current, change); err != nil {
+			return fmt.Errorf("restore member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Member{}, err
+	}
+	return restored, nil
+}
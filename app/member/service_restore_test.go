@@ -0,0 +1,84 @@
+package member
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServiceRestore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 3
+		member.DeletedAt = now
+
+		restored := member
+		restored.DeletedAt = time.Time{}
+		restored.Version = 4
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Restore(contextBackground(), member, recorded(ActionRestore, member, restored, now)).Return(nil)
+		})
+
+		got, err := svc.Restore(contextBackground(), "john", 3)
+		assert.NoError(t, err)
+		assert.False(t, got.Deleted())
+		assert.Equal(t, time.Time{}, got.DeletedAt)
+		assert.Equal(t, int64(4), got.Version)
+	})
+
+	t.Run("should not write when not deleted", func(t *testing.T) {
+		member, _ := newFixture()
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		got, err := svc.Restore(contextBackground(), "john", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, member, got)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 3
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+		})
+
+		_, err := svc.Restore(contextBackground(), "john", 2)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
+		})
+
+		_, err := svc.Restore(contextBackground(), "unknown", 0)
+		assert.ErrorIs(t, err, ErrorMemberNotFound)
+	})
+
+	t.Run("storage error on restore", func(t *testing.T) {
+		member, now := newFixture()
+		member.DeletedAt = now
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Restore(contextBackground(), member, mock.Anything).Return(errors.New("restore err"))
+		})
+
+		_, err := svc.Restore(contextBackground(), "john", 0)
+		assert.ErrorContains(t, err, "restore err")
+	})
+}
@@ -30,6 +30,15 @@ func newServiceWithMocks(t interface {
 
 	storage := newMockStorager(t)
 
+	caller := newMockCaller(t)
+	caller.EXPECT().Actor(mock.Anything).Return("admin").Maybe()
+	caller.EXPECT().TraceID(mock.Anything).Return("4bf92f3577b34da6a3ce929d0e0e4736").Maybe()
+
+	tx := newMockTransactor(t)
+	tx.EXPECT().InTx(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+
 	if clockFn != nil {
 		clockFn(clock)
 	}
@@ -37,7 +46,20 @@ func newServiceWithMocks(t interface {
 		storageFn(storage)
 	}
 
-	return NewService(storage, clock)
+	return NewService(storage, tx, clock, caller, AgePolicy{MinYears: 15, MaxYears: 60, Location: time.UTC})
+}
+
+// recorded is the change the service records through the mocked caller.
+func recorded(action Action, before, after Member, at time.Time) Change {
+	return Change{
+		Username:  after.Username,
+		Version:   after.Version,
+		Action:    action,
+		Actor:     "admin",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		Diff:      Diff(before, after),
+		ChangedAt: at,
+	}
 }
 
 func noClock() mockClockFn     { return nil }
@@ -46,3 +68,26 @@ func noStorage() mockStorageFn { return nil }
 func contextBackground() context.Context {
 	return context.Background()
 }
+
+type txContextKey struct{}
+
+// txContext is the ctx the transactor of withTx hands to the unit of work.
+func txContext() context.Context {
+	return context.WithValue(contextBackground(), txContextKey{}, "tx")
+}
+
+// withTx runs the units of work of svc with txContext, returning err as the transaction outcome.
+func withTx(t interface {
+	mock.TestingT
+	Cleanup(func())
+}, svc *service, err error) *service {
+	tx := newMockTransactor(t)
+	tx.EXPECT().InTx(contextBackground(), mock.Anything).RunAndReturn(func(_ context.Context, fn func(context.Context) error) error {
+		if fnErr := fn(txContext()); fnErr != nil {
+			return fnErr
+		}
+		return err
+	}).Once()
+	svc.tx = tx
+	return svc
+}
@@ -5,19 +5,37 @@ import (
 	"fmt"
 )
 
-func (s *service) Update(ctx context.Context, username string, m Member) error {
-	_, found, err := s.storage.Member(ctx, username)
-	if err != nil {
-		return fmt.Errorf("remove member: %w", err)
-	}
-	if !found {
-		return ErrorMemberNotFound
-	}
+func (s *service) Update(ctx context.Context, username string, version int64, m Member) (Member, error) {
+	var updated Member
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		current, found, err := s.storage.Member(ctx, username)
+		if err != nil {
+			return fmt.Errorf("update member: %w", err)
+		}
+		if !found || current.Deleted() {
+			return ErrorMemberNotFound
+		}
+		if version != 0 && version != current.Version {
+			return ErrorVersionMismatch
+		}
+
+		if err := s.checkBirthday(current, m); err != nil {
+			return err
+		}
+
+		m.Username = username
+		m.Version = current.Version
+		updated = m
+		updated.Version++
 
-	m.Username = username
-	err = s.storage.Update(ctx, m)
+		err = s.storage.Update(ctx, m, s.change(ctx, ActionUpdate, current, updated, s.clock.Now()))
+		if err != nil {
+			return fmt.Errorf("update member: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("update member: %w", err)
+		return Member{}, err
 	}
-	return err
+	return updated, nil
 }
@@ -3,20 +3,89 @@ package member
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestServiceUpdate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
+		current, now := newFixture()
+		current.Version = 3
+		member := current
+		member.FirstName = "Johnny"
+		updated := member
+		updated.Version = 4
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(current, true, nil)
+			m.EXPECT().Update(contextBackground(), member, recorded(ActionUpdate, current, updated, now)).Return(nil)
+		})
+
+		got, err := svc.Update(contextBackground(), "john", 0, member)
+		assert.NoError(t, err)
+		assert.Equal(t, updated, got)
+	})
+
+	t.Run("success with matching version", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 3
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
+			m.EXPECT().Update(contextBackground(), member, mock.Anything).Return(nil)
+		})
+
+		_, err := svc.Update(contextBackground(), "john", 3, member)
+		assert.NoError(t, err)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
 		member, _ := newFixture()
+		member.Version = 3
 
 		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
-			m.EXPECT().Update(contextBackground(), member).Return(nil)
 		})
 
-		err := svc.Update(contextBackground(), "john", member)
+		_, err := svc.Update(contextBackground(), "john", 2, member)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+	})
+
+	t.Run("should validate changed birthday", func(t *testing.T) {
+		current, now := newFixture()
+		member := current
+		member.Birthday = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(current, true, nil)
+		})
+
+		_, err := svc.Update(contextBackground(), "john", 0, member)
+		assert.ErrorIs(t, err, ErrorMinAge)
+	})
+
+	t.Run("should keep unchanged birthday out of the policy", func(t *testing.T) {
+		current, now := newFixture()
+		current.Birthday = time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC)
+		member := current
+		member.LastName = "Smith"
+
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(contextBackground(), "john").Return(current, true, nil)
+			m.EXPECT().Update(contextBackground(), member, mock.Anything).Return(nil)
+		})
+
+		_, err := svc.Update(contextBackground(), "john", 0, member)
 		assert.NoError(t, err)
 	})
 
@@ -27,7 +96,7 @@ func TestServiceUpdate(t *testing.T) {
 			m.EXPECT().Member(contextBackground(), "unknown").Return(Member{}, false, nil)
 		})
 
-		err := svc.Update(contextBackground(), "unknown", member)
+		_, err := svc.Update(contextBackground(), "unknown", 0, member)
 		assert.ErrorIs(t, err, ErrorMemberNotFound)
 	})
 
@@ -38,19 +107,35 @@ func TestServiceUpdate(t *testing.T) {
 			m.EXPECT().Member(contextBackground(), "john").Return(Member{}, false, errors.New("db err"))
 		})
 
-		err := svc.Update(contextBackground(), "john", member)
+		_, err := svc.Update(contextBackground(), "john", 0, member)
 		assert.ErrorContains(t, err, "db err")
 	})
 
 	t.Run("storage error on update", func(t *testing.T) {
-		member, _ := newFixture()
+		member, now := newFixture()
 
-		svc := newServiceWithMocks(t, noClock(), func(m *mockStorager) {
+		svc := newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
 			m.EXPECT().Member(contextBackground(), "john").Return(member, true, nil)
-			m.EXPECT().Update(contextBackground(), member).Return(errors.New("update err"))
+			m.EXPECT().Update(contextBackground(), member, mock.Anything).Return(ErrorVersionMismatch)
 		})
 
-		err := svc.Update(contextBackground(), "john", member)
-		assert.ErrorContains(t, err, "update err")
+		_, err := svc.Update(contextBackground(), "john", 0, member)
+		assert.ErrorIs(t, err, ErrorVersionMismatch)
+	})
+	t.Run("should check and update in one transaction", func(t *testing.T) {
+		member, now := newFixture()
+		member.Version = 2
+
+		svc := withTx(t, newServiceWithMocks(t, func(c *mockClock) {
+			c.On("Now").Return(now)
+		}, func(m *mockStorager) {
+			m.EXPECT().Member(txContext(), "john").Return(member, true, nil)
+			m.EXPECT().Update(txContext(), mock.Anything, mock.Anything).Return(nil)
+		}), errors.New("commit err"))
+
+		_, err := svc.Update(contextBackground(), "john", 0, member)
+		assert.EqualError(t, err, "commit err")
 	})
 }
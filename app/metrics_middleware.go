@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/labstack/echo/v5"
+)
+
+// unmatchedRoute keeps 404 scans from creating one series per raw path.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware counts requests and observes latency by route template, status and business code.
+func MetricsMiddleware(reg *metrics.Registry) echo.MiddlewareFunc {
+	requests := metrics.NewCounterVec("http_requests_total", "Total HTTP requests.", "method", "route", "status", "code")
+	duration := metrics.NewHistogramVec("http_request_duration_seconds", "HTTP request latency in seconds.", nil, "method", "route", "status")
+	inFlight := metrics.NewGaugeVec("http_requests_in_flight", "HTTP requests currently being served.")
+	reg.MustRegister(requests, duration, inFlight)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			now := time.Now()
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			status := statusRecorder(ctx)
+			err := next(ctx)
+
+			route := ctx.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			method := ctx.Request().Method
+			code := strconv.Itoa(status(err))
+
+			requests.Inc(method, route, code, businessCode(ctx, err))
+			duration.Observe(time.Since(now).Seconds(), method, route, code)
+			return err
+		}
+	}
+}
+
+// businessCode prefers the code of a returned app.Error since the error handler has not run yet.
+func businessCode(ctx *echo.Context, err error) string {
+	var appErr Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	code, _ := ctx.Get(CodeKey).(string)
+	return code
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	newApp := func() (*echo.Echo, *metrics.Registry) {
+		reg := metrics.NewRegistry()
+		e := echo.New()
+		e.HTTPErrorHandler = errorHandler
+		e.Use(MetricsMiddleware(reg))
+		e.GET("/api/v1/members/:username", func(ctx *echo.Context) error {
+			if ctx.Param("username") == "missing" {
+				return NotFound(MemberNotFoundCode, MemberNotFoundMsg, errors.New("not found"))
+			}
+			return Ok(ctx, nil)
+		})
+		return e, reg
+	}
+
+	scrape := func(t *testing.T, reg *metrics.Registry) string {
+		sb := strings.Builder{}
+		require.NoError(t, reg.WriteText(&sb))
+		return sb.String()
+	}
+
+	t.Run("should count by route template and business code", func(t *testing.T) {
+		e, reg := newApp()
+
+		for _, username := range []string{"john", "jane", "missing"} {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/members/"+username, nil))
+		}
+
+		out := scrape(t, reg)
+		assert.Contains(t, out, `http_requests_total{method="GET",route="/api/v1/members/:username",status="200",code="0000"} 2`)
+		assert.Contains(t, out, `http_requests_total{method="GET",route="/api/v1/members/:username",status="400",code="1003"} 1`)
+		assert.Contains(t, out, `http_request_duration_seconds_count{method="GET",route="/api/v1/members/:username",status="200"} 2`)
+		assert.Contains(t, out, "http_requests_in_flight 0")
+	})
+
+	t.Run("should group unmatched paths", func(t *testing.T) {
+		e, reg := newApp()
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wp-admin", nil))
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/.env", nil))
+
+		out := scrape(t, reg)
+		assert.Contains(t, out, `http_requests_total{method="GET",route="unmatched",status="404",code=""} 2`)
+		assert.NotContains(t, out, "wp-admin")
+	})
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/pkg/database"
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+)
+
+// Module is a domain module plugged into EchoApp with Register.
+// It may also implement HealthModule, MigrationModule and HookModule.
+type Module interface {
+	Name() string
+	RegisterRoutes(app *EchoApp)
+}
+
+type HealthModule interface {
+	HealthCheckers() []health.Checker
+}
+
+// MigrationModule provides "<version>_<name>.up.sql" files applied before the module hooks start.
+type MigrationModule interface {
+	Migrations() fs.FS
+}
+
+type HookModule interface {
+	Hooks() []lifecycle.Hook
+}
+
+// Register adds the routes and health checks of every module not disabled by MODULE_DISABLED.
+func (app *EchoApp) Register(modules ...Module) {
+	for _, m := range modules {
+		if slices.Contains(app.cfg.Module.Disabled, m.Name()) {
+			slog.Info("module disabled", "module", m.Name())
+			continue
+		}
+
+		m.RegisterRoutes(app)
+		if hm, ok := m.(HealthModule); ok {
+			app.Health.Register(hm.HealthCheckers()...)
+		}
+		app.modules = append(app.modules, m)
+	}
+}
+
+func (app *EchoApp) Modules() []Module {
+	return app.modules
+}
+
+// ModuleHooks returns the lifecycle hooks of the registered modules, each depending on dependsOn.
+// When migration is enabled, a "migrate-<module>" hook runs the module migrations on db first.
+func (app *EchoApp) ModuleHooks(db *sqlx.DB, dependsOn ...string) []lifecycle.Hook {
+	var hooks []lifecycle.Hook
+	for _, m := range app.modules {
+		deps := slices.Clone(dependsOn)
+
+		if mm, ok := m.(MigrationModule); ok && app.cfg.Migration.Enable {
+			hooks = append(hooks, migrationHook(db, m.Name(), mm.Migrations(), dependsOn))
+			deps = append(deps, hooks[len(hooks)-1].Name)
+		}
+
+		if hm, ok := m.(HookModule); ok {
+			for _, h := range hm.Hooks() {
+				h.DependsOn = append(slices.Clone(deps), h.DependsOn...)
+				hooks = append(hooks, h)
+			}
+		}
+	}
+	return hooks
+}
+
+func migrationHook(db *sqlx.DB, name string, fsys fs.FS, dependsOn []string) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name:      "migrate-" + name,
+		DependsOn: dependsOn,
+		OnStart: func(ctx context.Context) error {
+			applied, err := database.Migrate(ctx, db, name, fsys)
+			if len(applied) > 0 {
+				slog.InfoContext(ctx, "migrated "+name, "versions", applied)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+type fakeModule struct {
+	name       string
+	migrations fstest.MapFS
+	started    bool
+}
+
+func (m *fakeModule) Name() string { return m.name }
+
+func (m *fakeModule) RegisterRoutes(app *EchoApp) {
+	app.GET("/"+m.name, func(ctx *echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+}
+
+func (m *fakeModule) HealthCheckers() []health.Checker {
+	return []health.Checker{health.NewChecker(m.name, func(context.Context) error { return nil })}
+}
+
+func (m *fakeModule) Migrations() fs.FS { return m.migrations }
+
+func (m *fakeModule) Hooks() []lifecycle.Hook {
+	return []lifecycle.Hook{{
+		Name: m.name + "-worker",
+		OnStart: func(context.Context) error {
+			m.started = true
+			return nil
+		},
+	}}
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("should register routes and health checks", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		e.Register(&fakeModule{name: "order"})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, e.Modules(), 1)
+		assert.Equal(t, "order", e.Health.Ready(t.Context()).Checks[0].Name)
+	})
+
+	t.Run("should skip disabled module", func(t *testing.T) {
+		e := NewEchoApp(config.Config{Module: config.Module{Disabled: []string{"order"}}})
+		e.Register(&fakeModule{name: "order"}, &fakeModule{name: "member"})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Len(t, e.Modules(), 1)
+		assert.Equal(t, "member", e.Modules()[0].Name())
+	})
+}
+
+func TestModuleHooks(t *testing.T) {
+	t.Run("should run migrations before module hooks", func(t *testing.T) {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		mod := &fakeModule{name: "order", migrations: fstest.MapFS{
+			"0001_init.up.sql": {Data: []byte("CREATE TABLE orders (id int)")},
+		}}
+		e := NewEchoApp(config.Config{Migration: config.Migration{Enable: true}})
+		e.Register(mod)
+
+		hooks := e.ModuleHooks(db, "database")
+		require.Len(t, hooks, 2)
+		assert.Equal(t, "migrate-order", hooks[0].Name)
+		assert.Equal(t, []string{"database"}, hooks[0].DependsOn)
+		assert.Equal(t, "order-worker", hooks[1].Name)
+		assert.Equal(t, []string{"database", "migrate-order"}, hooks[1].DependsOn)
+
+		lc := lifecycle.New(nil)
+		lc.Append(lifecycle.Hook{Name: "database"})
+		lc.Append(hooks...)
+		require.NoError(t, lc.Start(t.Context()))
+
+		assert.True(t, mod.started)
+		_, err = db.Exec("INSERT INTO orders (id) VALUES (1)")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should skip migrations when disabled", func(t *testing.T) {
+		e := NewEchoApp(config.Config{})
+		e.Register(&fakeModule{name: "order"})
+
+		hooks := e.ModuleHooks(nil)
+		require.Len(t, hooks, 1)
+		assert.Equal(t, "order-worker", hooks[0].Name)
+		assert.Empty(t, hooks[0].DependsOn)
+	})
+}
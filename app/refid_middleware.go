@@ -3,35 +3,52 @@ package app
 import (
 	"context"
 	"log/slog"
-	"strings"
 
-	"github.com/google/uuid"
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 	"github.com/labstack/echo/v5"
 )
 
-func RefIDMiddleware(key string, tags map[string]string) echo.MiddlewareFunc {
+func RefIDMiddleware(key string, cfg config.Log) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx *echo.Context) error {
-			refID := ctx.Request().Header.Get(key)
 			req := ctx.Request()
+
+			sc, ok := trace.FromContext(req.Context())
+			if !ok {
+				sc, ok = trace.Extract(req.Header)
+				if ok {
+					sc = sc.Child()
+				} else {
+					sc = trace.NewSpanContext()
+				}
+			}
+
+			refID := req.Header.Get(key)
 			if refID == "" {
 				ctx.Logger().DebugContext(req.Context(), "no refID", slog.String("key", key))
-				refID = uuid.NewString()
+				refID = sc.TraceID
 			}
 			ctx.Set(TraceIDKey, refID)
+			ctx.Set(W3CTraceIDKey, sc.TraceID)
+			ctx.Set(SpanIDKey, sc.SpanID)
 
-			if tag, ok := tags[req.URL.Path]; ok {
-				ctx.Set(TagKey, tag)
-			} else {
-				ctx.Set(TagKey, strings.ReplaceAll(req.URL.Path, "/", "-")[1:])
-			}
+			policy := policyOf(ctx, cfg)
+			ctx.Set(TagKey, policy.tag)
 
 			reqCtx := context.WithValue(req.Context(), key, refID)
-			ctx.SetRequest(req.WithContext(context.WithValue(reqCtx, TraceIDKey, refID)))
+			reqCtx = context.WithValue(reqCtx, TraceIDKey, refID)
+			ctx.SetRequest(req.WithContext(trace.ContextWith(reqCtx, sc)))
+
+			header := ctx.Response().Header()
+			header.Set(key, refID)
+			trace.Inject(header, sc)
 
 			ctx.SetLogger(ctx.Logger().With(
 				TraceIDKey, refID,
-				TagKey, ctx.Get(TagKey),
+				W3CTraceIDKey, sc.TraceID,
+				SpanIDKey, sc.SpanID,
+				TagKey, policy.tag,
 			))
 
 			return next(ctx)
@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/echotest"
 	"github.com/stretchr/testify/assert"
@@ -13,10 +15,11 @@ import (
 func TestRefIDMiddleware(t *testing.T) {
 	t.Run("should generate new refID when header not present", func(t *testing.T) {
 		ctx, _ := echotest.ContextConfig{
-			Request: httptest.NewRequest(http.MethodGet, "/api/v1/test", nil),
+			Request:   httptest.NewRequest(http.MethodGet, "/api/v1/test", nil),
+			RouteInfo: &echo.RouteInfo{Method: http.MethodGet, Path: "/api/v1/test"},
 		}.ToContextRecorder(t)
 
-		middleware := RefIDMiddleware("X-Ref-ID", nil)
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
 		handler := middleware(func(ctx *echo.Context) error {
 			traceID, _ := ctx.Get(TraceIDKey).(string)
 			assert.NotEmpty(t, traceID)
@@ -36,7 +39,7 @@ func TestRefIDMiddleware(t *testing.T) {
 			Request: req,
 		}.ToContextRecorder(t)
 
-		middleware := RefIDMiddleware("X-Ref-ID", nil)
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
 		handler := middleware(func(ctx *echo.Context) error {
 			traceID, _ := ctx.Get(TraceIDKey).(string)
 			assert.Equal(t, "custom-ref-id", traceID)
@@ -49,14 +52,15 @@ func TestRefIDMiddleware(t *testing.T) {
 
 	t.Run("should use tag from tags map when path matches", func(t *testing.T) {
 		ctx, _ := echotest.ContextConfig{
-			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members", nil),
+			Request:   httptest.NewRequest(http.MethodGet, "/api/v1/members", nil),
+			RouteInfo: &echo.RouteInfo{Method: http.MethodGet, Path: "/api/v1/members"},
 		}.ToContextRecorder(t)
 
 		tags := map[string]string{
 			"/api/v1/members": "member-tag",
 		}
 
-		middleware := RefIDMiddleware("X-Ref-ID", tags)
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{Tags: tags})
 		handler := middleware(func(ctx *echo.Context) error {
 			tag, _ := ctx.Get(TagKey).(string)
 			assert.Equal(t, "member-tag", tag)
@@ -67,3 +71,113 @@ func TestRefIDMiddleware(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestRefIDMiddlewareRouteTemplate(t *testing.T) {
+	t.Run("should derive tag from route template", func(t *testing.T) {
+		ctx, _ := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members/alice", nil),
+		}.ToContextRecorder(t)
+		ctx.SetPath("/api/v1/members/:username")
+
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
+		handler := middleware(func(ctx *echo.Context) error {
+			tag, _ := ctx.Get(TagKey).(string)
+			assert.Equal(t, "api-v1-members-username", tag)
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+	})
+
+	t.Run("should use tag from route policy", func(t *testing.T) {
+		ctx, _ := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/api/v1/members/bob", nil),
+		}.ToContextRecorder(t)
+		ctx.SetPath("/api/v1/members/:username")
+
+		cfg := config.Log{Routes: config.LogRoutes{
+			"GET /api/v1/members/:username": {Tag: "member-get"},
+		}}
+
+		middleware := RefIDMiddleware("X-Ref-ID", cfg)
+		handler := middleware(func(ctx *echo.Context) error {
+			tag, _ := ctx.Get(TagKey).(string)
+			assert.Equal(t, "member-get", tag)
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+	})
+}
+
+func TestRefIDMiddlewareTraceContext(t *testing.T) {
+	t.Run("should continue trace from traceparent", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+		req.Header.Set(trace.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set(trace.HeaderTraceState, "vendor=value")
+		ctx, rec := echotest.ContextConfig{
+			Request: req,
+		}.ToContextRecorder(t)
+
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
+		handler := middleware(func(ctx *echo.Context) error {
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ctx.Get(W3CTraceIDKey))
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ctx.Get(TraceIDKey), "ref id falls back to the trace id")
+			assert.NotEqual(t, "00f067aa0ba902b7", ctx.Get(SpanIDKey))
+
+			sc, ok := trace.FromContext(ctx.Request().Context())
+			assert.True(t, ok)
+			assert.Equal(t, ctx.Get(SpanIDKey), sc.SpanID)
+			assert.Equal(t, "vendor=value", sc.State)
+			return ctx.NoContent(http.StatusOK)
+		})
+
+		assert.NoError(t, handler(ctx))
+
+		sc, ok := trace.ParseTraceParent(rec.Header().Get(trace.HeaderTraceParent))
+		assert.True(t, ok)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", rec.Header().Get("X-Ref-ID"))
+		assert.Equal(t, "vendor=value", rec.Header().Get(trace.HeaderTraceState))
+	})
+
+	t.Run("should start new trace when traceparent invalid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+		req.Header.Set(trace.HeaderTraceParent, "invalid")
+		req.Header.Set("X-Ref-ID", "custom-ref-id")
+		ctx, rec := echotest.ContextConfig{
+			Request: req,
+		}.ToContextRecorder(t)
+
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
+		handler := middleware(func(ctx *echo.Context) error {
+			traceID, _ := ctx.Get(W3CTraceIDKey).(string)
+			assert.Len(t, traceID, 32)
+			assert.Equal(t, "custom-ref-id", ctx.Get(TraceIDKey))
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+		assert.Equal(t, "custom-ref-id", rec.Header().Get("X-Ref-ID"))
+		assert.NotEmpty(t, rec.Header().Get(trace.HeaderTraceParent))
+	})
+}
+
+func TestRefIDMiddlewareServerSpan(t *testing.T) {
+	t.Run("should use span context started by tracing middleware", func(t *testing.T) {
+		sc := trace.NewSpanContext()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+		ctx, _ := echotest.ContextConfig{
+			Request: req.WithContext(trace.ContextWith(req.Context(), sc)),
+		}.ToContextRecorder(t)
+
+		middleware := RefIDMiddleware("X-Ref-ID", config.Log{})
+		handler := middleware(func(ctx *echo.Context) error {
+			assert.Equal(t, sc.TraceID, ctx.Get(W3CTraceIDKey))
+			assert.Equal(t, sc.SpanID, ctx.Get(SpanIDKey))
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx))
+	})
+}
@@ -1,41 +1,101 @@
 package app
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
 )
 
+// echoResponseWriter keeps the start of the body, up to cfg.MaxBodySize, and logs the response once
+// it completes. Flush, Hijack and Unwrap are forwarded so streaming and http.ResponseController work.
 type echoResponseWriter struct {
 	http.ResponseWriter
 	logger *slog.Logger
 	ctx    context.Context
 	status int
+	size   int64
+	body   bytes.Buffer
+	logged bool
 	url    string
 	now    time.Time
+	cfg    config.Log
+	policy routePolicy
 }
 
 func (w *echoResponseWriter) WriteHeader(status int) {
-	w.status = status
+	if w.status == 0 {
+		w.status = status
+	}
 	w.ResponseWriter.WriteHeader(status)
 }
 
 func (w *echoResponseWriter) Write(b []byte) (int, error) {
-	body := string(b)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
 
-	if w.status == http.StatusOK || w.status == http.StatusCreated {
-		w.logger.InfoContext(w.ctx, fmt.Sprintf("response %d %s", w.status, w.url),
-			"body", body,
-			"latency", time.Since(w.now).String(),
-		)
-	} else {
-		w.logger.ErrorContext(w.ctx, fmt.Sprintf("response %d %s", w.status, w.url),
-			"body", body,
-			"latency", time.Since(w.now).String(),
-		)
+	// one extra byte tells logBody the body was truncated
+	if remain := w.cfg.MaxBodySize + 1 - w.body.Len(); remain > 0 {
+		w.body.Write(b[:min(len(b), remain)])
 	}
 
-	return w.ResponseWriter.Write(b)
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *echoResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *echoResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *echoResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode prefers echo.Response since Context.JSON sets its Status without calling WriteHeader.
+func (w *echoResponseWriter) statusCode() int {
+	if resp, err := echo.UnwrapResponse(w.ResponseWriter); err == nil && resp.Committed {
+		return resp.Status
+	}
+	if w.status != 0 {
+		return w.status
+	}
+	return http.StatusOK
+}
+
+// logResponse writes the single response log, later calls are no-ops.
+func (w *echoResponseWriter) logResponse() {
+	if w.logged {
+		return
+	}
+	w.logged = true
+
+	status := w.statusCode()
+
+	body := omittedBody
+	if w.policy.body && allowContentType(w.cfg.ContentTypes, w.Header().Get(echo.HeaderContentType)) {
+		body = logBody(w.body.Bytes(), w.cfg)
+	}
+
+	level := w.policy.level
+	if status >= http.StatusBadRequest {
+		level = slog.LevelError
+	}
+	w.logger.Log(w.ctx, level, fmt.Sprintf("response %d %s", status, w.url),
+		"body", body,
+		"size", w.size,
+		"latency", time.Since(w.now).String(),
+	)
 }
@@ -1,25 +1,48 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/kongsakchai/gotemplate/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestResponseWriter(status int) *echoResponseWriter {
-	rec := httptest.NewRecorder()
+	w, _ := newLoggedResponseWriter(config.Log{MaxBodySize: defaultLogBodySize})
+	w.status = status
+	return w
+}
+
+func newLoggedResponseWriter(cfg config.Log) (*echoResponseWriter, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
 	return &echoResponseWriter{
-		ResponseWriter: rec,
-		logger:         slog.Default(),
+		ResponseWriter: httptest.NewRecorder(),
+		logger:         slog.New(slog.NewJSONHandler(buf, nil)),
 		ctx:            nil,
-		status:         0,
 		url:            "/test",
 		now:            time.Now(),
+		cfg:            cfg,
+		policy:         routePolicy{body: true},
+	}, buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		lines = append(lines, m)
 	}
+	return lines
 }
 
 func TestEchoResponseWriter_WriteHeader(t *testing.T) {
@@ -52,4 +75,80 @@ func TestEchoResponseWriter_Write(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 10, n)
 	})
+
+	t.Run("should default status to 200 when header not written", func(t *testing.T) {
+		w := newTestResponseWriter(0)
+		_, err := w.Write([]byte("body"))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.status)
+	})
+
+	t.Run("should buffer body up to the cap", func(t *testing.T) {
+		w, _ := newLoggedResponseWriter(config.Log{MaxBodySize: 4})
+		w.Write([]byte("abc"))
+		w.Write([]byte("defgh"))
+
+		assert.Equal(t, "abcde", w.body.String())
+		assert.Equal(t, int64(8), w.size)
+	})
+}
+
+func TestEchoResponseWriter_LogResponse(t *testing.T) {
+	t.Run("should log once with total size", func(t *testing.T) {
+		w, buf := newLoggedResponseWriter(config.Log{MaxBodySize: 4})
+		w.Write([]byte("data: 1\n"))
+		w.Write([]byte("data: 2\n"))
+		w.logResponse()
+		w.logResponse()
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "response 200 /test", lines[0]["msg"])
+		assert.Equal(t, "data"+truncatedSuffix, lines[0]["body"])
+		assert.Equal(t, float64(16), lines[0]["size"])
+	})
+
+	t.Run("should log 200 when nothing written", func(t *testing.T) {
+		w, buf := newLoggedResponseWriter(config.Log{MaxBodySize: 4})
+		w.logResponse()
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "response 200 /test", lines[0]["msg"])
+		assert.Equal(t, "INFO", lines[0]["level"])
+	})
+
+	t.Run("should log error status as error", func(t *testing.T) {
+		w, buf := newLoggedResponseWriter(config.Log{MaxBodySize: 4})
+		w.WriteHeader(http.StatusBadRequest)
+		w.logResponse()
+
+		assert.Equal(t, "ERROR", logLines(t, buf)[0]["level"])
+	})
+}
+
+func TestEchoResponseWriter_ResponseController(t *testing.T) {
+	t.Run("should flush through the wrapper", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w := &echoResponseWriter{ResponseWriter: rec}
+
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		assert.True(t, rec.Flushed)
+
+		w.Flush()
+	})
+
+	t.Run("should unwrap to the original writer", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w := &echoResponseWriter{ResponseWriter: rec}
+
+		assert.Same(t, rec, w.Unwrap())
+	})
+
+	t.Run("should report hijack unsupported", func(t *testing.T) {
+		w := &echoResponseWriter{ResponseWriter: httptest.NewRecorder()}
+
+		_, _, err := w.Hijack()
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+	})
 }
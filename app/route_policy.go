@@ -0,0 +1,76 @@
+package app
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/kongsakchai/gotemplate/pkg/logger"
+	"github.com/labstack/echo/v5"
+)
+
+const routePolicyKey = "routePolicy"
+
+var tagReplacer = strings.NewReplacer("/", "-", ":", "", "*", "")
+
+type routePolicy struct {
+	tag   string
+	body  bool
+	level slog.Level
+	skip  bool
+}
+
+// routeOf returns the Echo route template, falling back to the raw path when no route matched.
+func routeOf(ctx *echo.Context) string {
+	if path := ctx.Path(); path != "" {
+		return path
+	}
+	return ctx.Request().URL.Path
+}
+
+func lookupRoute[T any](m map[string]T, ctx *echo.Context) (T, bool) {
+	route := routeOf(ctx)
+	for _, key := range []string{ctx.Request().Method + " " + route, route, ctx.Request().URL.Path} {
+		if v, ok := m[key]; ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func newRoutePolicy(ctx *echo.Context, cfg config.Log) routePolicy {
+	route, _ := lookupRoute(cfg.Routes, ctx)
+
+	tag := route.Tag
+	if tag == "" {
+		tag, _ = lookupRoute(cfg.Tags, ctx)
+	}
+	if tag == "" {
+		tag = strings.Trim(tagReplacer.Replace(routeOf(ctx)), "-")
+	}
+
+	skip := route.Skip || slices.Contains(cfg.SkipPaths, routeOf(ctx)) || slices.Contains(cfg.SkipPaths, ctx.Request().URL.Path)
+	if route.SampleRate > 0 && rand.Float64() >= route.SampleRate {
+		skip = true
+	}
+
+	return routePolicy{
+		tag:   tag,
+		body:  !route.NoBody,
+		level: logger.ParseLevel(route.Level),
+		skip:  skip,
+	}
+}
+
+// policyOf returns the policy resolved by RefIDMiddleware so both middlewares agree on sampling.
+func policyOf(ctx *echo.Context, cfg config.Log) routePolicy {
+	if p, ok := ctx.Get(routePolicyKey).(routePolicy); ok {
+		return p
+	}
+	p := newRoutePolicy(ctx, cfg)
+	ctx.Set(routePolicyKey, p)
+	return p
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/echotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouteContext(t *testing.T, method, path, route string) *echo.Context {
+	ctx, _ := echotest.ContextConfig{
+		Request: httptest.NewRequest(method, path, nil),
+	}.ToContextRecorder(t)
+	ctx.SetPath(route)
+	return ctx
+}
+
+func TestNewRoutePolicy(t *testing.T) {
+	t.Run("should return default policy", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/api/v1/members/alice", "/api/v1/members/:username")
+
+		policy := newRoutePolicy(ctx, config.Log{})
+		assert.Equal(t, routePolicy{tag: "api-v1-members-username", body: true, level: slog.LevelInfo}, policy)
+	})
+
+	t.Run("should prefer method specific route", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodDelete, "/api/v1/members/alice", "/api/v1/members/:username")
+
+		policy := newRoutePolicy(ctx, config.Log{Routes: config.LogRoutes{
+			"/api/v1/members/:username":        {Tag: "member"},
+			"DELETE /api/v1/members/:username": {Tag: "member-remove", NoBody: true, Level: "debug"},
+		}})
+		assert.Equal(t, routePolicy{tag: "member-remove", body: false, level: slog.LevelDebug}, policy)
+	})
+
+	t.Run("should fallback to tags map", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/api/v1/members", "/api/v1/members")
+
+		policy := newRoutePolicy(ctx, config.Log{Tags: map[string]string{"/api/v1/members": "members"}})
+		assert.Equal(t, "members", policy.tag)
+	})
+
+	t.Run("should skip when route opt out", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/health", "/health")
+
+		policy := newRoutePolicy(ctx, config.Log{Routes: config.LogRoutes{"/health": {Skip: true}}})
+		assert.True(t, policy.skip)
+	})
+
+	t.Run("should skip when path in skip paths", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/health", "/health")
+
+		policy := newRoutePolicy(ctx, config.Log{SkipPaths: []string{"/health"}})
+		assert.True(t, policy.skip)
+	})
+
+	t.Run("should skip when sampled out", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/health", "/health")
+
+		policy := newRoutePolicy(ctx, config.Log{Routes: config.LogRoutes{"/health": {SampleRate: 1e-12}}})
+		assert.True(t, policy.skip)
+	})
+}
+
+func TestPolicyOf(t *testing.T) {
+	t.Run("should resolve policy once per request", func(t *testing.T) {
+		ctx := newRouteContext(t, http.MethodGet, "/test", "/test")
+
+		first := policyOf(ctx, config.Log{})
+		second := policyOf(ctx, config.Log{Routes: config.LogRoutes{"/test": {Tag: "other"}}})
+		assert.Equal(t, first, second)
+	})
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+// dropHeader in a route override removes the header for that route.
+const dropHeader = "-"
+
+// CORSMiddleware allows the configured origins, "*" allows any origin.
+func CORSMiddleware(cfg config.CORS) echo.MiddlewareFunc {
+	allowAny := false
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			allowAny = true
+		}
+	}
+	if allowAny && cfg.AllowCredentials {
+		panic("cors: * as allowed origin with credentials is insecure, list the origins instead")
+	}
+
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+		UnsafeAllowOriginFunc: func(_ *echo.Context, origin string) (string, bool, error) {
+			if allowAny {
+				return "*", true, nil
+			}
+			for _, pattern := range cfg.AllowOrigins {
+				if matchOrigin(pattern, origin) {
+					return origin, true, nil
+				}
+			}
+			return "", false, nil
+		},
+	})
+}
+
+// matchOrigin compares case-insensitively, a "*" in pattern matches one or more host labels.
+func matchOrigin(pattern, origin string) bool {
+	pattern, origin = strings.ToLower(pattern), strings.ToLower(origin)
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return pattern == origin
+	}
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+
+	for _, r := range origin[len(prefix) : len(origin)-len(suffix)] {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// SecurityHeadersMiddleware sets the headers before the handler runs so error responses carry them too.
+// HSTS is only sent over https, including behind a proxy setting X-Forwarded-Proto.
+func SecurityHeadersMiddleware(cfg config.Security) echo.MiddlewareFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			route, _ := lookupRoute(cfg.Routes, ctx)
+			if route.Skip {
+				return next(ctx)
+			}
+
+			header := ctx.Response().Header()
+			if cfg.ContentTypeNosniff {
+				header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+			}
+			setHeader(header, echo.HeaderXFrameOptions, cfg.FrameOptions, route.FrameOptions)
+			setHeader(header, echo.HeaderContentSecurityPolicy, cfg.CSP, route.CSP)
+			setHeader(header, echo.HeaderReferrerPolicy, cfg.ReferrerPolicy, route.ReferrerPolicy)
+			if hsts != "" && ctx.Scheme() == "https" {
+				header.Set(echo.HeaderStrictTransportSecurity, hsts)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func setHeader(header http.Header, key, value, override string) {
+	if override != "" {
+		value = override
+	}
+	if value != "" && value != dropHeader {
+		header.Set(key, value)
+	}
+}
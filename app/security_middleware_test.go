@@ -0,0 +1,153 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/config"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWith(mw echo.MiddlewareFunc, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(mw)
+	e.GET("/api", func(ctx *echo.Context) error { return ctx.String(http.StatusOK, "ok") })
+	e.GET("/docs/*", func(ctx *echo.Context) error { return ctx.String(http.StatusOK, "docs") })
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	t.Run("should allow any origin with wildcard", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://foo.com")
+
+		rec := serveWith(CORSMiddleware(config.CORS{AllowOrigins: []string{"*"}}), req)
+		assert.Equal(t, "*", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("should allow subdomain pattern", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+
+		rec := serveWith(CORSMiddleware(config.CORS{
+			AllowOrigins:     []string{"https://*.example.com"},
+			AllowCredentials: true,
+		}), req)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	})
+
+	t.Run("should not allow unknown origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.com")
+
+		rec := serveWith(CORSMiddleware(config.CORS{AllowOrigins: []string{"https://*.example.com"}}), req)
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should answer preflight with configured methods and max age", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://example.com")
+
+		rec := serveWith(CORSMiddleware(config.CORS{
+			AllowOrigins: []string{"https://example.com"},
+			AllowMethods: []string{http.MethodGet, http.MethodPost},
+			AllowHeaders: []string{"Content-Type"},
+			MaxAge:       10 * time.Minute,
+		}), req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "GET,POST", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+		assert.Equal(t, "Content-Type", rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+		assert.Equal(t, "600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+	})
+
+	t.Run("should panic when wildcard with credentials", func(t *testing.T) {
+		assert.Panics(t, func() {
+			CORSMiddleware(config.CORS{AllowOrigins: []string{"*"}, AllowCredentials: true})
+		})
+	})
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"https://example.com", "https://example.com", true},
+		{"https://example.com", "HTTPS://Example.com", true},
+		{"https://example.com", "http://example.com", false},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://.example.com", false},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evil.com/.example.com", false},
+		{"http://localhost:*", "http://localhost:3000", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchOrigin(tt.pattern, tt.origin))
+		})
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	cfg := config.Security{
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeNosniff:    true,
+		FrameOptions:          "DENY",
+		CSP:                   "default-src 'none'",
+		ReferrerPolicy:        "no-referrer",
+		Routes: config.SecurityRoutes{
+			"/docs/*": {CSP: "default-src 'self'", FrameOptions: "-"},
+		},
+	}
+
+	t.Run("should set default headers", func(t *testing.T) {
+		rec := serveWith(SecurityHeadersMiddleware(cfg), httptest.NewRequest(http.MethodGet, "/api", nil))
+
+		assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+		assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+		assert.Equal(t, "default-src 'none'", rec.Header().Get(echo.HeaderContentSecurityPolicy))
+		assert.Equal(t, "no-referrer", rec.Header().Get(echo.HeaderReferrerPolicy))
+		assert.Empty(t, rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	})
+
+	t.Run("should set hsts over https", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set(echo.HeaderXForwardedProto, "https")
+
+		rec := serveWith(SecurityHeadersMiddleware(cfg), req)
+		assert.Equal(t, "max-age=3600; includeSubDomains", rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	})
+
+	t.Run("should apply route override", func(t *testing.T) {
+		rec := serveWith(SecurityHeadersMiddleware(cfg), httptest.NewRequest(http.MethodGet, "/docs/index.html", nil))
+
+		assert.Equal(t, "default-src 'self'", rec.Header().Get(echo.HeaderContentSecurityPolicy))
+		assert.Empty(t, rec.Header().Get(echo.HeaderXFrameOptions))
+		assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+	})
+
+	t.Run("should skip route", func(t *testing.T) {
+		cfg := cfg
+		cfg.Routes = config.SecurityRoutes{"GET /api": {Skip: true}}
+
+		rec := serveWith(SecurityHeadersMiddleware(cfg), httptest.NewRequest(http.MethodGet, "/api", nil))
+		assert.Empty(t, rec.Header().Get(echo.HeaderXContentTypeOptions))
+	})
+
+	t.Run("should keep headers on error response", func(t *testing.T) {
+		rec := serveWith(SecurityHeadersMiddleware(cfg), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+	})
+}
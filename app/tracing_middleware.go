@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+	"github.com/labstack/echo/v5"
+)
+
+// TracingMiddleware starts a server span per request, continuing the caller's traceparent if any.
+func TracingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+			route := routeOf(ctx)
+
+			reqCtx := req.Context()
+			if remote, ok := trace.Extract(req.Header); ok {
+				reqCtx = trace.ContextWith(reqCtx, remote)
+			}
+
+			reqCtx, span := trace.Start(reqCtx, req.Method+" "+route,
+				trace.WithKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					slog.String("http.request.method", req.Method),
+					slog.String("http.route", route),
+					slog.String("url.path", req.URL.Path),
+				),
+			)
+			defer span.End()
+			ctx.SetRequest(req.WithContext(reqCtx))
+
+			status := statusRecorder(ctx)
+			err := next(ctx)
+
+			// a 4xx is the client's fault, so only a 5xx or an unknown error marks the span as failed
+			code := status(err)
+			span.SetAttributes(slog.Int("http.response.status_code", code))
+			switch {
+			case code >= http.StatusInternalServerError && err != nil:
+				span.RecordError(err)
+			case code >= http.StatusInternalServerError:
+				span.SetStatus(trace.StatusError, http.StatusText(code))
+			default:
+				var appErr Error
+				if errors.As(err, &appErr) && appErr.Code != "" {
+					span.SetAttributes(slog.String("error.code", appErr.Code))
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// statusRecorder must be called before next so it holds the original echo response,
+// the returned func reports the status the error handler will write for err.
+func statusRecorder(ctx *echo.Context) func(err error) int {
+	resp, _ := echo.UnwrapResponse(ctx.Response())
+
+	return func(err error) int {
+		if err != nil {
+			var appErr Error
+			if errors.As(err, &appErr) {
+				return appErr.HTTPCode
+			}
+			var sc echo.HTTPStatusCoder
+			if errors.As(err, &sc) && sc.StatusCode() != 0 {
+				return sc.StatusCode()
+			}
+			return http.StatusInternalServerError
+		}
+		if resp == nil || resp.Status == 0 {
+			return http.StatusOK
+		}
+		return resp.Status
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/echotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []trace.SpanData
+}
+
+func (r *spanRecorder) Export(_ context.Context, spans []trace.SpanData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *spanRecorder) Shutdown(_ context.Context) error { return nil }
+
+func useSpanRecorder(t *testing.T) (*trace.Tracer, *spanRecorder) {
+	recorder := &spanRecorder{}
+	tracer := trace.NewTracer(recorder)
+	trace.SetDefault(tracer)
+	t.Cleanup(func() { trace.SetDefault(nil) })
+	return tracer, recorder
+}
+
+func hasAttr(attrs []slog.Attr, key string, value any) bool {
+	for _, a := range attrs {
+		if a.Key == key && a.Value.Resolve().Any() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Run("should record server span with parent", func(t *testing.T) {
+		tracer, recorder := useSpanRecorder(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members/john", nil)
+		req.Header.Set(trace.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		ctx, _ := echotest.ContextConfig{
+			Request:   req,
+			RouteInfo: &echo.RouteInfo{Method: http.MethodGet, Path: "/api/v1/members/:username"},
+		}.ToContextRecorder(t)
+
+		handler := TracingMiddleware()(func(ctx *echo.Context) error {
+			sc, ok := trace.FromContext(ctx.Request().Context())
+			assert.True(t, ok)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID)
+			return ctx.NoContent(http.StatusCreated)
+		})
+
+		require.NoError(t, handler(ctx))
+		require.NoError(t, tracer.Shutdown(t.Context()))
+		require.Len(t, recorder.spans, 1)
+
+		span := recorder.spans[0]
+		assert.Equal(t, "GET /api/v1/members/:username", span.Name)
+		assert.Equal(t, trace.SpanKindServer, span.Kind)
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID)
+		assert.Equal(t, trace.StatusUnset, span.Status)
+		assert.True(t, hasAttr(span.Attributes, "http.response.status_code", int64(http.StatusCreated)))
+	})
+
+	t.Run("should record error status from app error", func(t *testing.T) {
+		tracer, recorder := useSpanRecorder(t)
+
+		ctx, _ := echotest.ContextConfig{
+			Request: httptest.NewRequest(http.MethodGet, "/test", nil),
+		}.ToContextRecorder(t)
+
+		handler := TracingMiddleware()(func(ctx *echo.Context) error {
+			return InternalError(InternalErrorCode, "internal error", errors.New("db down"))
+		})
+
+		assert.Error(t, handler(ctx))
+		require.NoError(t, tracer.Shutdown(t.Context()))
+		require.Len(t, recorder.spans, 1)
+		assert.Equal(t, trace.StatusError, recorder.spans[0].Status)
+		assert.True(t, hasAttr(recorder.spans[0].Attributes, "http.response.status_code", int64(http.StatusInternalServerError)))
+	})
+
+	t.Run("should not mark a client error as failed", func(t *testing.T) {
+		for _, err := range []error{
+			NotFound(MemberNotFoundCode, MemberNotFoundMsg, errors.New("not found")),
+			BadRequest(BadRequestCode, BadRequestMsg, errors.New("invalid")),
+		} {
+			tracer, recorder := useSpanRecorder(t)
+
+			ctx, _ := echotest.ContextConfig{
+				Request: httptest.NewRequest(http.MethodGet, "/test", nil),
+			}.ToContextRecorder(t)
+
+			handler := TracingMiddleware()(func(ctx *echo.Context) error { return err })
+
+			assert.Error(t, handler(ctx))
+			require.NoError(t, tracer.Shutdown(t.Context()))
+			require.Len(t, recorder.spans, 1)
+
+			appErr := err.(Error)
+			span := recorder.spans[0]
+			assert.Equal(t, trace.StatusUnset, span.Status, appErr.Code)
+			assert.True(t, hasAttr(span.Attributes, "http.response.status_code", int64(appErr.HTTPCode)))
+			assert.True(t, hasAttr(span.Attributes, "error.code", appErr.Code))
+			assert.False(t, hasAttr(span.Attributes, "error", appErr.Error()))
+		}
+	})
+}
+
+func TestStatusRecorder(t *testing.T) {
+	testcases := []struct {
+		title string
+		err   error
+		want  int
+	}{
+		{"should return 200 when nothing written", nil, http.StatusOK},
+		{"should return app error code", Conflict("", "", nil), http.StatusConflict},
+		{"should return echo error code", echo.NewHTTPError(http.StatusNotFound, ""), http.StatusNotFound},
+		{"should return 500 for unknown error", errors.New("unknown"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.title, func(t *testing.T) {
+			ctx, _ := echotest.ContextConfig{
+				Request: httptest.NewRequest(http.MethodGet, "/test", nil),
+			}.ToContextRecorder(t)
+
+			status := statusRecorder(ctx)
+			assert.Equal(t, tc.want, status(tc.err))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package app
+
+import (
+	"github.com/kongsakchai/gotemplate/pkg/buildinfo"
+	"github.com/labstack/echo/v5"
+)
+
+// VersionHandler reports the version and VCS information of the running binary.
+func VersionHandler(ctx *echo.Context) error {
+	return Ok(ctx, buildinfo.Get())
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/buildinfo"
+	"github.com/labstack/echo/v5/echotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandler(t *testing.T) {
+	ctx, rec := echotest.ContextConfig{}.ToContextRecorder(t)
+
+	require.NoError(t, VersionHandler(ctx))
+
+	var resp struct {
+		Data buildinfo.Info `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, buildinfo.Get(), resp.Data)
+}
@@ -29,67 +29,76 @@ package main
 
 import "github.com/kongsakchai/gotemplate/app"
 
-// swagger:route GET /health common none
-// Health check endpoint.
+// swagger:route GET /livez common none
+// Liveness endpoint, dependencies are not checked.
+// responses:
+//   200: livenessResponse
+
+// swagger:response livenessResponse
+type LivenessResponseWrapper struct {
+	// in:body
+	Body struct {
+		app.SwaggerSuccessResponse
+		// example: alive
+		Message string `json:"message"`
+	}
+}
+
+// swagger:route GET /version common none
+// Version, VCS revision and commit time of the running binary.
+// responses:
+//   200: versionResponse
+
+// swagger:response versionResponse
+type VersionResponseWrapper struct {
+	// in:body
+	Body struct {
+		app.SwaggerSuccessResponse
+		Data struct {
+			// example: v1.2.3
+			Version string `json:"version"`
+			// example: 704b17e9c1f6b2a0d4e3f5a6b7c8d9e0f1a2b3c4
+			Revision string `json:"revision"`
+			// example: 2026-01-01T00:00:00Z
+			Time string `json:"time"`
+			// example: false
+			Dirty bool `json:"dirty"`
+			// example: github.com/kongsakchai/gotemplate
+			Module string `json:"module"`
+			// example: go1.26.1
+			GoVersion string `json:"goVersion"`
+		} `json:"data"`
+	}
+}
+
+// swagger:route GET /readyz common none
+// Readiness endpoint with a report of every registered check. /health is an alias.
 // responses:
 //   200: healthResponse
-//   500: errorDatabaseResponse
+//   503: errorServiceNotReadyResponse
 
 // swagger:response healthResponse
 type HealthResponseWrapper struct {
 	// in:body
 	Body struct {
 		app.SwaggerSuccessResponse
-		// example: health
-		Message string `json:"message"`
+		// example: healthy
+		Message string           `json:"message"`
+		Data    app.HealthReport `json:"data"`
 	}
 }
 
 // swagger:route GET /metrics common none
-// Metrics endpoint.
+// Prometheus metrics in text exposition format.
+// produces:
+// - text/plain
 // responses:
 //   200: metricsResponse
 
 // swagger:response metricsResponse
 type MetricsResponseWrapper struct {
 	// in:body
-	Body struct {
-		app.SwaggerSuccessResponse
-		Data struct {
-			/*
-				{
-				  "code": "0000",
-				  "success": true,
-				  "data": {
-				    "alloc": "0.77 MB",
-				    "heapIdle": "2.34 MB",
-				    "heapInuse": "1.38 MB",
-				    "heapReleased": "2.28 MB",
-				    "stackInuse": "0.28 MB",
-				    "stackSys": "0.28 MB",
-				    "sysAlloc": "8.02 MB",
-				    "totalAlloc": "0.77 MB"
-				  }
-				}
-			*/
-			// example: 0.77 MB
-			Alloc string `json:"alloc"`
-			// example: 2.34 MB
-			HeapIdle string `json:"heapIdle"`
-			// example: 1.38 MB
-			HeapInuse string `json:"heapInuse"`
-			// example: 2.28 MB
-			HeapReleased string `json:"heapReleased"`
-			// example: 0.28 MB
-			StackInuse string `json:"stackInuse"`
-			// example: 0.28 MB
-			StackSys string `json:"stackSys"`
-			// example: 0.28 MB
-			SysAlloc string `json:"sysAlloc"`
-			// example: 0.28 MB
-			TotalAlloc string `json:"totalAlloc"`
-			// example: 0.28 MB
-
-		} `json:"data"`
-	}
+	// example: # TYPE http_requests_total counter
+	// http_requests_total{method="GET",route="/api/v1/members/:username",status="200",code="0000"} 1
+	Body string
 }
@@ -4,102 +4,147 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
-	"runtime"
-	"runtime/debug"
 	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/kongsakchai/gotemplate/app"
 	"github.com/kongsakchai/gotemplate/app/member"
+	"github.com/kongsakchai/gotemplate/pkg/buildinfo"
+	"github.com/kongsakchai/gotemplate/pkg/cache"
 	"github.com/kongsakchai/gotemplate/pkg/clock"
 	"github.com/kongsakchai/gotemplate/pkg/config"
 	"github.com/kongsakchai/gotemplate/pkg/database"
+	"github.com/kongsakchai/gotemplate/pkg/health"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
 	"github.com/kongsakchai/gotemplate/pkg/logger"
+	"github.com/kongsakchai/gotemplate/pkg/metrics"
+	"github.com/kongsakchai/gotemplate/pkg/trace"
+	"github.com/kongsakchai/gotemplate/pkg/tuning"
 	"github.com/labstack/echo/v5"
 )
 
 const gracefulTimeout = time.Second * 10
 
-const (
-	Byte uint64 = 1 << (10 * iota)
-	KB
-	MB
-)
-
-func init() {
-	if os.Getenv("GOMAXPROCS") == "" {
-		runtime.GOMAXPROCS(1) // 0 - 999m
-	}
-	if os.Getenv("GOMEMLIMIT") != "" {
-		debug.SetMemoryLimit(-1) // GOMEMLIMIT
+func main() {
+	// run returns instead of exiting so its deferred cleanups finish first
+	if err := run(); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 }
 
-func main() {
-	logger := logger.New()
+func run() error {
+	logger := logger.New().With(buildinfo.Get().LogAttr())
+	slog.SetDefault(logger)
 	cfg := config.Load(config.Env)
+	tuning.Apply(tuning.Config{MemoryRatio: cfg.Runtime.MemoryRatio}, logger)
+	lc := lifecycle.New(logger)
+
+	if cfg.Trace.Enable {
+		tracer := trace.New(trace.Config{
+			ServiceName: cfg.App.Name,
+			Exporter:    cfg.Trace.Exporter,
+			Endpoint:    cfg.Trace.Endpoint,
+			Timeout:     cfg.Trace.Timeout,
+		})
+		trace.SetDefault(tracer)
+		lc.Append(lifecycle.Hook{Name: "tracer", OnStop: tracer.Shutdown})
+	}
 
-	db, close := database.NewMySQL(cfg.Database.URL)
-	defer close(context.Background())
+	db, err := database.Open("mysql", cfg.Database.URL)
+	if err != nil {
+		return fmt.Errorf("open database fail: %w", err)
+	}
+	lc.Append(database.Hook("database", db))
+	dependsOn := []string{"database"}
 
 	clock := clock.New()
 
 	app := app.NewEchoApp(cfg)
 	app.Logger = logger
 
-	app.GET("/health", healthCheck(nil))
-	app.GET("/metrics", metrics())
+	app.Metrics.MustRegister(metrics.NewDBStatsCollector("main", db))
+	app.Health.Register(health.DB("database", db))
+	if cfg.Redis.Host != "" {
+		rdb := cache.NewRedis(cache.RedisConfig{
+			Host:     cfg.Redis.Host,
+			Port:     cfg.Redis.Port,
+			Username: cfg.Redis.Username,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+			Timeout:  cfg.Redis.Timeout,
+		})
+		lc.Append(cache.Hook("redis", rdb))
+		dependsOn = append(dependsOn, "redis")
+		app.Metrics.MustRegister(metrics.NewRedisCollector("main", rdb))
+		app.Health.Register(health.Redis("redis", rdb))
+	}
 
-	memberMo := member.NewModule(member.External{DB: db, Clock: clock})
-	memberMo.Handler.RegisterMemberHandler(app)
+	registerSystemRoutes(app)
+	app.GET("/metrics", echo.WrapHandler(app.Metrics.Handler()))
+
+	app.Register(
+		member.NewModule(member.External{
+			DB:      db,
+			Clock:   clock,
+			Metrics: app.Metrics,
+			Age: member.AgePolicy{
+				MinYears: cfg.Member.MinAge,
+				MaxYears: cfg.Member.MaxAge,
+				Location: cfg.Member.TimeZone,
+			},
+			Retention:     time.Duration(cfg.Member.RetentionDays) * 24 * time.Hour,
+			PurgeInterval: cfg.Member.PurgeInterval,
+		}),
+	)
+
+	for _, h := range app.ModuleHooks(db, dependsOn...) {
+		lc.Append(h)
+		dependsOn = append(dependsOn, h.Name)
+	}
+	lc.Append(app.Hook(fmt.Sprintf(":%s", cfg.App.Port), gracefulTimeout, dependsOn...))
+	if cfg.Admin.Enable {
+		lc.Append(app.AdminHook(gracefulTimeout))
+	}
 
-	runApp(app, cfg, gracefulTimeout)
+	return runApp(lc, cfg, gracefulTimeout)
 }
 
-func runApp(app *app.EchoApp, cfg config.Config, gracefulTimeout time.Duration) {
-	slog.Info(cfg.App.Name, "version", cfg.App.Version, "env", config.Env)
-	slog.Info("listening on port " + cfg.App.Port)
+func runApp(lc *lifecycle.Lifecycle, cfg config.Config, gracefulTimeout time.Duration) error {
+	info := buildinfo.Get()
+	slog.Info(cfg.App.Name,
+		"env", config.Env,
+		"version", info.Version,
+		"revision", info.Revision,
+		"time", info.Time,
+		"dirty", info.Dirty,
+	)
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
 	defer stop()
 
-	if err := app.Start(ctx, fmt.Sprintf(":%s", cfg.App.Port), gracefulTimeout); err != nil && err != http.ErrServerClosed {
-		slog.Error("shutting down the server: " + err.Error())
-		return
+	if err := lc.Start(ctx); err != nil {
+		return fmt.Errorf("starting the app: %w", err)
 	}
+	slog.Info("listening on port " + cfg.App.Port)
 
-	slog.Info("bye bye")
-}
+	<-ctx.Done()
 
-func healthCheck(db *sqlx.DB) echo.HandlerFunc {
-	return func(ctx *echo.Context) error {
-		if db != nil && db.Ping() != nil {
-			return app.Fail(ctx, app.InternalError(app.DatabaseNotReadyCode, app.DatabaseNotReadyMsg, nil))
-		}
-		return app.Ok(ctx, nil, "healthy")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), gracefulTimeout)
+	defer cancel()
+
+	if err := lc.Stop(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down the app: %w", err)
 	}
-}
 
-func toMB(b uint64) string {
-	return fmt.Sprintf("%.2f MB", float64(b)/float64(MB))
+	slog.Info("bye bye")
+	return nil
 }
 
-func metrics() echo.HandlerFunc {
-	return func(ctx *echo.Context) error {
-		var mem runtime.MemStats
-		runtime.ReadMemStats(&mem)
-		return app.Ok(ctx, map[string]string{
-			"alloc":        toMB(mem.Alloc),
-			"totalAlloc":   toMB(mem.TotalAlloc),
-			"sysAlloc":     toMB(mem.Sys),
-			"heapInuse":    toMB(mem.HeapInuse),
-			"heapIdle":     toMB(mem.HeapIdle),
-			"heapReleased": toMB(mem.HeapReleased),
-			"stackInuse":   toMB(mem.StackInuse),
-			"stackSys":     toMB(mem.StackSys),
-		})
-	}
+func registerSystemRoutes(e *app.EchoApp) {
+	e.GET("/version", app.VersionHandler)
+	e.GET("/livez", app.LivenessHandler(e.Health))
+	e.GET("/readyz", app.ReadinessHandler(e.Health))
+	e.GET("/health", app.ReadinessHandler(e.Health))
 }
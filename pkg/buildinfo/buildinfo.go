@@ -0,0 +1,88 @@
+package buildinfo
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+const develVersion = "(devel)"
+
+// Overrides set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/kongsakchai/gotemplate/pkg/buildinfo.version=v1.2.3"
+var (
+	version    string
+	revision   string
+	commitTime string
+	dirty      string
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Dirty     bool   `json:"dirty"`
+	Module    string `json:"module,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+var get = sync.OnceValue(func() Info {
+	bi, _ := debug.ReadBuildInfo()
+	return read(bi, overrides{version: version, revision: revision, time: commitTime, dirty: dirty})
+})
+
+// Get returns the build info of the running binary, ldflags overrides win over the embedded VCS data.
+func Get() Info {
+	return get()
+}
+
+// LogAttr groups the build info as a constant attribute for loggers.
+func (i Info) LogAttr() slog.Attr {
+	return slog.Group("build",
+		slog.String("version", i.Version),
+		slog.String("revision", i.Revision),
+	)
+}
+
+type overrides struct {
+	version  string
+	revision string
+	time     string
+	dirty    string
+}
+
+func read(bi *debug.BuildInfo, o overrides) Info {
+	info := Info{Version: develVersion}
+	if bi != nil {
+		info.Module = bi.Main.Path
+		info.GoVersion = bi.GoVersion
+		if bi.Main.Version != "" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				info.Time = s.Value
+			case "vcs.modified":
+				info.Dirty = s.Value == "true"
+			}
+		}
+	}
+
+	if o.version != "" {
+		info.Version = o.version
+	}
+	if o.revision != "" {
+		info.Revision = o.revision
+	}
+	if o.time != "" {
+		info.Time = o.time
+	}
+	if o.dirty != "" {
+		info.Dirty = o.dirty == "true"
+	}
+	return info
+}
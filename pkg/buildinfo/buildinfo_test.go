@@ -0,0 +1,65 @@
+package buildinfo
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	bi := &debug.BuildInfo{
+		GoVersion: "go1.26.1",
+		Main:      debug.Module{Path: "github.com/kongsakchai/gotemplate", Version: "v1.0.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	t.Run("should read embedded build info", func(t *testing.T) {
+		info := read(bi, overrides{})
+
+		assert.Equal(t, Info{
+			Version:   "v1.0.0",
+			Revision:  "abc123",
+			Time:      "2026-01-02T03:04:05Z",
+			Dirty:     true,
+			Module:    "github.com/kongsakchai/gotemplate",
+			GoVersion: "go1.26.1",
+		}, info)
+	})
+
+	t.Run("should prefer ldflags overrides", func(t *testing.T) {
+		info := read(bi, overrides{version: "v2.0.0", revision: "def456", time: "2026-02-01T00:00:00Z", dirty: "false"})
+
+		assert.Equal(t, "v2.0.0", info.Version)
+		assert.Equal(t, "def456", info.Revision)
+		assert.Equal(t, "2026-02-01T00:00:00Z", info.Time)
+		assert.False(t, info.Dirty)
+	})
+
+	t.Run("should fall back to devel without build info", func(t *testing.T) {
+		info := read(nil, overrides{})
+
+		assert.Equal(t, Info{Version: develVersion}, info)
+	})
+}
+
+func TestGet(t *testing.T) {
+	info := Get()
+
+	assert.NotEmpty(t, info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.Equal(t, info, Get())
+}
+
+func TestLogAttr(t *testing.T) {
+	attr := Info{Version: "v1.0.0", Revision: "abc123"}.LogAttr()
+
+	assert.Equal(t, "build", attr.Key)
+	assert.Equal(t, slog.KindGroup, attr.Value.Kind())
+	assert.Equal(t, []slog.Attr{slog.String("version", "v1.0.0"), slog.String("revision", "abc123")}, attr.Value.Group())
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Hook pings the client on start and closes it on stop.
+func Hook(name string, client *redis.Client) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		},
+		OnStop: func(_ context.Context) error {
+			return client.Close()
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook(t *testing.T) {
+	t.Run("should fail to start when redis is unreachable and close on stop", func(t *testing.T) {
+		rd := NewRedis(RedisConfig{
+			Host: "localhost",
+			Port: "63799",
+		})
+		hook := Hook("redis", rd)
+
+		assert.Equal(t, "redis", hook.Name)
+		assert.Error(t, hook.OnStart(t.Context()))
+		assert.NoError(t, hook.OnStop(t.Context()))
+	})
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -15,16 +16,27 @@ type Config struct {
 	Database  Database
 	Redis     Redis
 	Log       Log
+	Trace     Trace
+	Health    Health
+	Module    Module
+	Admin     Admin
+	Runtime   Runtime
+	CORS      CORS
+	Security  Security
+	Limit     Limit
+	LoadShed  LoadShed
+	Member    Member
 }
 
 type App struct {
-	Name    string `env:"APP_NAME" envDefault:"gotemplate"`
-	Port    string `env:"APP_PORT" envDefault:"8080"`
-	Version string `env:"APP_VERSION" envDefault:"0.0.1"`
+	Name string `env:"APP_NAME" envDefault:"gotemplate"`
+	Port string `env:"APP_PORT" envDefault:"8080"`
 }
 
 type Header struct {
 	RefIDKey string `env:"HEADER_REF_ID_KEY" envDefault:"X-Ref-ID"`
+	// ActorKey names a gateway injected header trusted as the audit actor, empty ignores it.
+	ActorKey string `env:"HEADER_ACTOR_KEY"`
 }
 
 type Migration struct {
@@ -35,22 +47,194 @@ type Migration struct {
 }
 
 type Database struct {
-	URL string `env:"DATABASE_URL"`
+	URL string `env:"DATABASE_URL" redact:"true"`
 }
 
 type Redis struct {
 	Host     string        `env:"REDIS_HOST"`
 	Port     string        `env:"REDIS_PORT"`
 	Username string        `env:"REDIS_USERNAME"`
-	Password string        `env:"REDIS_PASSWORD"`
+	Password string        `env:"REDIS_PASSWORD" redact:"true"`
 	DB       int           `env:"REDIS_DB" envDefault:"0"`
 	Timeout  time.Duration `env:"REDIS_TIMEOUT" envDefault:"10m"`
 }
 
 type Log struct {
-	Enable     bool              `env:"LOG_ENABLE"`
-	HttpEnable bool              `env:"LOG_HTTP_ENABLE"`
-	Tags       map[string]string `env:"LOG_TAGS" envSeparator:"," envKeyValSeparator:":"`
+	Enable       bool              `env:"LOG_ENABLE"`
+	HttpEnable   bool              `env:"LOG_HTTP_ENABLE"`
+	Tags         map[string]string `env:"LOG_TAGS" envSeparator:"," envKeyValSeparator:":"`
+	MaskFields   []string          `env:"LOG_MASK_FIELDS" envSeparator:"," envDefault:"password,birthday"`
+	MaxBodySize  int               `env:"LOG_MAX_BODY_SIZE" envDefault:"4096"`
+	ContentTypes []string          `env:"LOG_CONTENT_TYPES" envSeparator:"," envDefault:"application/json,*+json,text/plain"`
+	SkipPaths    []string          `env:"LOG_SKIP_PATHS" envSeparator:","`
+	Routes       LogRoutes         `env:"LOG_ROUTES"`
+}
+
+// LogRoute overrides HTTP logging of a route. A zero SampleRate logs every request.
+type LogRoute struct {
+	Tag        string  `json:"tag"`
+	NoBody     bool    `json:"noBody"`
+	SampleRate float64 `json:"sampleRate"`
+	Level      string  `json:"level"`
+	Skip       bool    `json:"skip"`
+}
+
+// LogRoutes is keyed by Echo route template, with or without method:
+// {"GET /api/v1/members/:username":{"tag":"member","noBody":true}}
+type LogRoutes map[string]LogRoute
+
+func (r *LogRoutes) UnmarshalText(text []byte) error {
+	return json.Unmarshal(text, (*map[string]LogRoute)(r))
+}
+
+type Trace struct {
+	Enable   bool          `env:"TRACE_ENABLE"`
+	Exporter string        `env:"TRACE_EXPORTER" envDefault:"stdout"`
+	Endpoint string        `env:"TRACE_OTLP_ENDPOINT" envDefault:"http://localhost:4318"`
+	Timeout  time.Duration `env:"TRACE_TIMEOUT" envDefault:"10s"`
+}
+
+type Health struct {
+	Timeout  time.Duration `env:"HEALTH_TIMEOUT" envDefault:"2s"`
+	CacheTTL time.Duration `env:"HEALTH_CACHE_TTL" envDefault:"5s"`
+}
+
+type Module struct {
+	Disabled []string `env:"MODULE_DISABLED" envSeparator:","`
+}
+
+// Member.RetentionDays of 0 keeps soft deleted members forever. MinAge and MaxAge are whole years
+// on today's date in TimeZone, MaxAge 0 has no upper limit.
+type Member struct {
+	RetentionDays int            `env:"MEMBER_RETENTION_DAYS" envDefault:"0"`
+	PurgeInterval time.Duration  `env:"MEMBER_PURGE_INTERVAL" envDefault:"1h"`
+	MinAge        int            `env:"MEMBER_MIN_AGE" envDefault:"15"`
+	MaxAge        int            `env:"MEMBER_MAX_AGE" envDefault:"60"`
+	TimeZone      *time.Location `env:"MEMBER_TIME_ZONE" envDefault:"UTC"`
+}
+
+type Admin struct {
+	Enable bool   `env:"ADMIN_ENABLE"`
+	Bind   string `env:"ADMIN_BIND" envDefault:"127.0.0.1"`
+	Port   string `env:"ADMIN_PORT" envDefault:"6060"`
+}
+
+type Runtime struct {
+	MemoryRatio float64 `env:"RUNTIME_MEMORY_RATIO" envDefault:"0.9"`
+}
+
+// CORS origins may use one wildcard for the subdomain, e.g. https://*.example.com.
+// Prefix the keys with the environment (LOCAL_CORS_ALLOW_ORIGINS) to allow different origins per env.
+type CORS struct {
+	AllowOrigins     []string      `env:"CORS_ALLOW_ORIGINS" envSeparator:"," envDefault:"*"`
+	AllowMethods     []string      `env:"CORS_ALLOW_METHODS" envSeparator:","`
+	AllowHeaders     []string      `env:"CORS_ALLOW_HEADERS" envSeparator:","`
+	ExposeHeaders    []string      `env:"CORS_EXPOSE_HEADERS" envSeparator:","`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"0s"`
+}
+
+type Security struct {
+	HSTSMaxAge            time.Duration  `env:"SECURITY_HSTS_MAX_AGE" envDefault:"8760h"`
+	HSTSIncludeSubdomains bool           `env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS" envDefault:"true"`
+	ContentTypeNosniff    bool           `env:"SECURITY_CONTENT_TYPE_NOSNIFF" envDefault:"true"`
+	FrameOptions          string         `env:"SECURITY_FRAME_OPTIONS" envDefault:"DENY"`
+	CSP                   string         `env:"SECURITY_CSP" envDefault:"default-src 'none'; frame-ancestors 'none'"`
+	ReferrerPolicy        string         `env:"SECURITY_REFERRER_POLICY" envDefault:"no-referrer"`
+	Routes                SecurityRoutes `env:"SECURITY_ROUTES"`
+}
+
+// SecurityRoute overrides headers of a route. Empty values keep the default, "-" drops the header.
+type SecurityRoute struct {
+	FrameOptions   string `json:"frameOptions"`
+	CSP            string `json:"csp"`
+	ReferrerPolicy string `json:"referrerPolicy"`
+	Skip           bool   `json:"skip"`
+}
+
+// SecurityRoutes is keyed by Echo route template like LogRoutes:
+// {"/docs/*":{"csp":"default-src 'self'","frameOptions":"SAMEORIGIN"}}
+type SecurityRoutes map[string]SecurityRoute
+
+func (r *SecurityRoutes) UnmarshalText(text []byte) error {
+	return json.Unmarshal(text, (*map[string]SecurityRoute)(r))
+}
+
+// Limit bounds request time and body size, Groups override it per route prefix.
+type Limit struct {
+	Timeout       time.Duration `env:"LIMIT_TIMEOUT" envDefault:"30s"`
+	TimeoutStatus int           `env:"LIMIT_TIMEOUT_STATUS" envDefault:"504"`
+	BodySize      int64         `env:"LIMIT_BODY_SIZE" envDefault:"1048576"`
+	Groups        LimitGroups   `env:"LIMIT_GROUPS"`
+}
+
+// LimitGroup zero values inherit the defaults.
+type LimitGroup struct {
+	Timeout  time.Duration `json:"timeout"`
+	BodySize int64         `json:"bodySize"`
+}
+
+func (g *LimitGroup) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Timeout  string `json:"timeout"`
+		BodySize int64  `json:"bodySize"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	g.BodySize = raw.BodySize
+	g.Timeout = 0
+	if raw.Timeout != "" {
+		d, err := time.ParseDuration(raw.Timeout)
+		if err != nil {
+			return fmt.Errorf("limit timeout: %w", err)
+		}
+		g.Timeout = d
+	}
+	return nil
+}
+
+// LimitGroups is keyed by route prefix, the longest matching prefix wins:
+// {"/api/v1/members":{"timeout":"5s","bodySize":65536}}
+type LimitGroups map[string]LimitGroup
+
+func (r *LimitGroups) UnmarshalText(text []byte) error {
+	return json.Unmarshal(text, (*map[string]LimitGroup)(r))
+}
+
+// LoadShed caps in-flight requests with an adaptive limit, priority routes are never shed.
+type LoadShed struct {
+	Enable         bool          `env:"LOAD_SHED_ENABLE" envDefault:"false"`
+	InitialLimit   int           `env:"LOAD_SHED_INITIAL_LIMIT" envDefault:"20"`
+	MinLimit       int           `env:"LOAD_SHED_MIN_LIMIT" envDefault:"4"`
+	MaxLimit       int           `env:"LOAD_SHED_MAX_LIMIT" envDefault:"200"`
+	Latency        time.Duration `env:"LOAD_SHED_LATENCY" envDefault:"500ms"`
+	Backoff        float64       `env:"LOAD_SHED_BACKOFF" envDefault:"0.9"`
+	RetryAfter     time.Duration `env:"LOAD_SHED_RETRY_AFTER" envDefault:"1s"`
+	PriorityRoutes []string      `env:"LOAD_SHED_PRIORITY_ROUTES" envSeparator:"," envDefault:"/livez,/readyz,/health,/metrics,/version"`
+	LatencyRoutes  LatencyRoutes `env:"LOAD_SHED_LATENCY_ROUTES"`
+}
+
+// LatencyRoutes overrides the latency target per route prefix, the longest matching prefix wins
+// and 0 keeps the route out of the latency signal: {"/api/v1/members/import":"0s"}
+type LatencyRoutes map[string]time.Duration
+
+func (r *LatencyRoutes) UnmarshalText(text []byte) error {
+	var raw map[string]string
+	if err := json.Unmarshal(text, &raw); err != nil {
+		return err
+	}
+	routes := make(LatencyRoutes, len(raw))
+	for prefix, value := range raw {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("latency of %s: %w", prefix, err)
+		}
+		routes[prefix] = d
+	}
+	*r = routes
+	return nil
 }
 
 var config Config
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,15 +15,89 @@ func TestLoad(t *testing.T) {
 		os.Clearenv()
 		t.Setenv("APP_NAME", "TestApp")
 		t.Setenv("APP_PORT", "8080")
-		t.Setenv("APP_VERSION", "1.0.0")
 
 		expectConfig := App{
-			Name:    "TestApp",
-			Port:    "8080",
-			Version: "1.0.0",
+			Name: "TestApp",
+			Port: "8080",
 		}
 
 		cfg := Load(Env)
 		assert.Equal(t, expectConfig, cfg.App)
+		assert.Equal(t, 15, cfg.Member.MinAge)
+		assert.Equal(t, 60, cfg.Member.MaxAge)
+		assert.Equal(t, "UTC", cfg.Member.TimeZone.String())
+		assert.Empty(t, cfg.Header.ActorKey)
+		assert.False(t, cfg.LoadShed.Enable)
+		assert.Zero(t, cfg.Member.RetentionDays)
+	})
+}
+
+func TestLogRoutes(t *testing.T) {
+	t.Run("should parse routes from json", func(t *testing.T) {
+		var routes LogRoutes
+		err := routes.UnmarshalText([]byte(`{"GET /api/v1/members/:username":{"tag":"member","noBody":true,"sampleRate":0.5,"level":"debug"}}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, LogRoutes{
+			"GET /api/v1/members/:username": {Tag: "member", NoBody: true, SampleRate: 0.5, Level: "debug"},
+		}, routes)
+	})
+
+	t.Run("should return error when invalid json", func(t *testing.T) {
+		var routes LogRoutes
+		err := routes.UnmarshalText([]byte(`{invalid`))
+		assert.Error(t, err)
+	})
+}
+
+func TestSecurityRoutes(t *testing.T) {
+	t.Run("should parse routes from json", func(t *testing.T) {
+		var routes SecurityRoutes
+		err := routes.UnmarshalText([]byte(`{"/docs/*":{"csp":"default-src 'self'","frameOptions":"-","skip":false}}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, SecurityRoutes{
+			"/docs/*": {CSP: "default-src 'self'", FrameOptions: "-"},
+		}, routes)
+	})
+
+	t.Run("should return error when invalid json", func(t *testing.T) {
+		var routes SecurityRoutes
+		err := routes.UnmarshalText([]byte(`{invalid`))
+		assert.Error(t, err)
+	})
+}
+
+func TestLimitGroups(t *testing.T) {
+	t.Run("should parse groups from json", func(t *testing.T) {
+		var groups LimitGroups
+		err := groups.UnmarshalText([]byte(`{"/api/v1/members":{"timeout":"5s","bodySize":1024},"/upload":{"bodySize":2048}}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, LimitGroups{
+			"/api/v1/members": {Timeout: 5 * time.Second, BodySize: 1024},
+			"/upload":         {BodySize: 2048},
+		}, groups)
+	})
+
+	t.Run("should return error when invalid timeout", func(t *testing.T) {
+		var groups LimitGroups
+		err := groups.UnmarshalText([]byte(`{"/api":{"timeout":"soon"}}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestLatencyRoutes(t *testing.T) {
+	t.Run("should parse routes from json", func(t *testing.T) {
+		var routes LatencyRoutes
+		err := routes.UnmarshalText([]byte(`{"/api/v1/members/import":"0s","/reports":"5s"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, LatencyRoutes{"/api/v1/members/import": 0, "/reports": 5 * time.Second}, routes)
+	})
+
+	t.Run("should return error when invalid latency", func(t *testing.T) {
+		var routes LatencyRoutes
+		assert.Error(t, routes.UnmarshalText([]byte(`{"/api":"soon"}`)))
 	})
 }
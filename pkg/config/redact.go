@@ -0,0 +1,25 @@
+package config
+
+import "reflect"
+
+const redacted = "***"
+
+// Redacted returns a copy of cfg where non-empty string fields tagged `redact:"true"` are masked.
+func Redacted(cfg Config) Config {
+	v := reflect.ValueOf(&cfg).Elem()
+	redact(v)
+	return cfg
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := v.Field(i)
+		switch {
+		case f.Kind() == reflect.Struct:
+			redact(f)
+		case t.Field(i).Tag.Get("redact") == "true" && f.Kind() == reflect.String && f.String() != "":
+			f.SetString(redacted)
+		}
+	}
+}
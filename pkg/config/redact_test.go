@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Run("should mask tagged fields only", func(t *testing.T) {
+		cfg := Config{
+			App:      App{Name: "gotemplate"},
+			Database: Database{URL: "root:secret@tcp(localhost:3306)/example"},
+			Redis:    Redis{Host: "localhost", Password: "secret"},
+		}
+
+		got := Redacted(cfg)
+
+		assert.Equal(t, "gotemplate", got.App.Name)
+		assert.Equal(t, "***", got.Database.URL)
+		assert.Equal(t, "localhost", got.Redis.Host)
+		assert.Equal(t, "***", got.Redis.Password)
+		assert.Equal(t, "secret", cfg.Redis.Password)
+	})
+
+	t.Run("should keep empty secrets empty", func(t *testing.T) {
+		got := Redacted(Config{})
+
+		assert.Empty(t, got.Database.URL)
+		assert.Empty(t, got.Redis.Password)
+	})
+}
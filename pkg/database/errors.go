@@ -0,0 +1,108 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// Classes of driver errors, errors.Is matches them on the result of Classify.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+	ErrDeadlock            = errors.New("deadlock")
+	ErrSerialization       = errors.New("serialization failure")
+)
+
+var classes = []error{
+	ErrUniqueViolation,
+	ErrForeignKeyViolation,
+	ErrNotNullViolation,
+	ErrCheckViolation,
+	ErrDeadlock,
+	ErrSerialization,
+}
+
+var mysqlClasses = map[uint16]error{
+	1062: ErrUniqueViolation, // ER_DUP_ENTRY
+	1586: ErrUniqueViolation, // ER_DUP_ENTRY_WITH_KEY_NAME
+	1216: ErrForeignKeyViolation,
+	1217: ErrForeignKeyViolation,
+	1451: ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED_2
+	1452: ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW_2
+	1048: ErrNotNullViolation,    // ER_BAD_NULL_ERROR
+	1364: ErrNotNullViolation,    // ER_NO_DEFAULT_FOR_FIELD
+	3819: ErrCheckViolation,      // ER_CHECK_CONSTRAINT_VIOLATED
+	1213: ErrDeadlock,            // ER_LOCK_DEADLOCK
+}
+
+// postgresClasses are keyed by SQLSTATE.
+var postgresClasses = map[string]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23502": ErrNotNullViolation,
+	"23514": ErrCheckViolation,
+	"40P01": ErrDeadlock,
+	"40001": ErrSerialization,
+}
+
+// sqliteClasses are keyed by extended result code.
+var sqliteClasses = map[int]error{
+	1555: ErrUniqueViolation, // SQLITE_CONSTRAINT_PRIMARYKEY
+	2067: ErrUniqueViolation, // SQLITE_CONSTRAINT_UNIQUE
+	787:  ErrForeignKeyViolation,
+	1299: ErrNotNullViolation,
+	275:  ErrCheckViolation,
+	5:    ErrSerialization, // SQLITE_BUSY
+	517:  ErrSerialization, // SQLITE_BUSY_SNAPSHOT
+}
+
+// sqliteError is implemented by the modernc.org/sqlite errors, matched by method so the driver
+// stays out of binaries that do not use it.
+type sqliteError interface {
+	error
+	Code() int
+}
+
+// Classify wraps a MySQL, PostgreSQL or SQLite error with its class, so errors.Is matches both the
+// class and the driver error. Other errors, and errors already classified, are returned unchanged.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, class := range classes {
+		if errors.Is(err, class) {
+			return err
+		}
+	}
+
+	var (
+		class     error
+		mysqlErr  *mysql.MySQLError
+		pqErr     *pq.Error
+		sqliteErr sqliteError
+	)
+	switch {
+	case errors.As(err, &mysqlErr):
+		class = mysqlClasses[mysqlErr.Number]
+	case errors.As(err, &pqErr):
+		class = postgresClasses[pqErr.SQLState()]
+	case errors.As(err, &sqliteErr):
+		class = sqliteClasses[sqliteErr.Code()]
+	}
+	if class == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", class, err)
+}
+
+// Retryable reports whether err is a deadlock or serialization failure, which succeed when the
+// whole transaction runs again.
+func Retryable(err error) bool {
+	err = Classify(err)
+	return errors.Is(err, ErrDeadlock) || errors.Is(err, ErrSerialization)
+}
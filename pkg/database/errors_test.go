@@ -0,0 +1,88 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Run("should classify mysql and postgres errors", func(t *testing.T) {
+		cases := []struct {
+			err   error
+			class error
+		}{
+			{&mysql.MySQLError{Number: 1062}, ErrUniqueViolation},
+			{&mysql.MySQLError{Number: 1452}, ErrForeignKeyViolation},
+			{&mysql.MySQLError{Number: 1048}, ErrNotNullViolation},
+			{&mysql.MySQLError{Number: 3819}, ErrCheckViolation},
+			{&mysql.MySQLError{Number: 1213}, ErrDeadlock},
+			{&pq.Error{Code: "23505"}, ErrUniqueViolation},
+			{&pq.Error{Code: "23503"}, ErrForeignKeyViolation},
+			{&pq.Error{Code: "23502"}, ErrNotNullViolation},
+			{&pq.Error{Code: "23514"}, ErrCheckViolation},
+			{&pq.Error{Code: "40P01"}, ErrDeadlock},
+			{&pq.Error{Code: "40001"}, ErrSerialization},
+		}
+		for _, c := range cases {
+			err := Classify(fmt.Errorf("insert: %w", c.err))
+
+			assert.ErrorIs(t, err, c.class, c.err.Error())
+			assert.ErrorIs(t, err, c.err)
+		}
+	})
+
+	t.Run("should classify sqlite errors", func(t *testing.T) {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		db.MustExec("PRAGMA foreign_keys = ON")
+		db.MustExec("CREATE TABLE parent (id int PRIMARY KEY, name text NOT NULL UNIQUE, age int CHECK (age > 0))")
+		db.MustExec("CREATE TABLE child (parent_id int REFERENCES parent(id))")
+		db.MustExec("INSERT INTO parent VALUES (1, 'a', 1)")
+
+		cases := []struct {
+			query string
+			class error
+		}{
+			{"INSERT INTO parent VALUES (1, 'b', 1)", ErrUniqueViolation},
+			{"INSERT INTO parent VALUES (2, 'a', 1)", ErrUniqueViolation},
+			{"INSERT INTO parent VALUES (3, NULL, 1)", ErrNotNullViolation},
+			{"INSERT INTO parent VALUES (4, 'd', 0)", ErrCheckViolation},
+			{"INSERT INTO child VALUES (9)", ErrForeignKeyViolation},
+		}
+		for _, c := range cases {
+			_, err := db.Exec(c.query)
+			assert.ErrorIs(t, Classify(err), c.class, c.query)
+		}
+	})
+
+	t.Run("should return other errors unchanged", func(t *testing.T) {
+		other := errors.New("other")
+		unknown := &mysql.MySQLError{Number: 1045}
+
+		assert.NoError(t, Classify(nil))
+		assert.Same(t, other, Classify(other))
+		assert.Equal(t, unknown, Classify(unknown))
+	})
+
+	t.Run("should not classify twice", func(t *testing.T) {
+		err := Classify(&pq.Error{Code: "23505"})
+
+		assert.Same(t, err, Classify(err))
+	})
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, Retryable(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, Retryable(&pq.Error{Code: "40001"}))
+	assert.False(t, Retryable(&pq.Error{Code: "23505"}))
+	assert.False(t, Retryable(nil))
+}
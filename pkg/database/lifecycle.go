@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kongsakchai/gotemplate/pkg/lifecycle"
+)
+
+// Open prepares a connection pool without connecting, use Hook to verify it on startup.
+func Open(driverName string, dataSourceName string) (*sqlx.DB, error) {
+	return sqlx.Open(driverName, dataSourceName)
+}
+
+// Hook pings db on start and closes the pool on stop.
+func Hook(name string, db *sqlx.DB) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name:    name,
+		OnStart: db.PingContext,
+		OnStop: func(_ context.Context) error {
+			return db.Close()
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	t.Run("should ping on start and close on stop", func(t *testing.T) {
+		db, err := Open("sqlite", ":memory:")
+		require.NoError(t, err)
+
+		hook := Hook("database", db)
+
+		assert.Equal(t, "database", hook.Name)
+		assert.NoError(t, hook.OnStart(t.Context()))
+		assert.NoError(t, hook.OnStop(t.Context()))
+		assert.Error(t, db.Ping())
+	})
+
+	t.Run("should fail to start when database is unreachable", func(t *testing.T) {
+		db, err := Open("mysql", "root:example@(localhost:1111)/example")
+		require.NoError(t, err)
+		defer db.Close()
+
+		assert.Error(t, Hook("database", db).OnStart(t.Context()))
+	})
+
+	t.Run("should error when unknow driver", func(t *testing.T) {
+		_, err := Open("unknow", "invalid")
+		assert.Error(t, err)
+	})
+}
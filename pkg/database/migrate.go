@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const migrationSuffix = ".up.sql"
+
+const createMigrationTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	source VARCHAR(64) NOT NULL,
+	version VARCHAR(64) NOT NULL,
+	applied_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (source, version)
+)`
+
+// Migrate applies the "<version>_<name>.up.sql" files at the root of fsys in version order.
+// Applied versions are recorded per source in schema_migrations so each module migrates independently.
+// It returns the versions applied by this call.
+func Migrate(ctx context.Context, db *sqlx.DB, source string, fsys fs.FS) ([]string, error) {
+	files, err := fs.Glob(fsys, "*"+migrationSuffix)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	if _, err := db.ExecContext(ctx, createMigrationTable); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var versions []string
+	err = db.SelectContext(ctx, &versions, db.Rebind("SELECT version FROM schema_migrations WHERE source = ?"), source)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+
+	var applied []string
+	for _, file := range files {
+		version, _, _ := strings.Cut(path.Base(file), "_")
+		version = strings.TrimSuffix(version, migrationSuffix)
+		if slices.Contains(versions, version) {
+			continue
+		}
+
+		if err := migrate(ctx, db, source, version, fsys, file); err != nil {
+			return applied, fmt.Errorf("migrate %s %s: %w", source, file, err)
+		}
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+func migrate(ctx context.Context, db *sqlx.DB, source, version string, fsys fs.FS, file string) error {
+	query, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(string(query)) != "" {
+		if _, err := tx.ExecContext(ctx, string(query)); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind("INSERT INTO schema_migrations (source, version, applied_at) VALUES (?, ?, ?)"),
+		source, version, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	setup := func(t *testing.T) *sqlx.DB {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	fsys := fstest.MapFS{
+		"0002_insert.up.sql":   {Data: []byte("INSERT INTO mock_data (id, value) VALUES (1, 10)")},
+		"0001_init.up.sql":     {Data: []byte("CREATE TABLE mock_data (id int, value int)")},
+		"0001_init.down.sql":   {Data: []byte("DROP TABLE mock_data")},
+		"0000_empty.up.sql":    {Data: []byte("")},
+		"readme.md":            {Data: []byte("not a migration")},
+		"nested/0003_x.up.sql": {Data: []byte("DROP TABLE mock_data")},
+	}
+
+	t.Run("should apply up migrations in version order once", func(t *testing.T) {
+		db := setup(t)
+
+		applied, err := Migrate(t.Context(), db, "mock", fsys)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0000", "0001", "0002"}, applied)
+
+		applied, err = Migrate(t.Context(), db, "mock", fsys)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		var value int
+		require.NoError(t, db.Get(&value, "SELECT value FROM mock_data WHERE id = 1"))
+		assert.Equal(t, 10, value)
+	})
+
+	t.Run("should track versions per source", func(t *testing.T) {
+		db := setup(t)
+		_, err := Migrate(t.Context(), db, "mock", fsys)
+		require.NoError(t, err)
+
+		applied, err := Migrate(t.Context(), db, "other", fstest.MapFS{
+			"0001_init.up.sql": {Data: []byte("CREATE TABLE other (id int)")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0001"}, applied)
+	})
+
+	t.Run("should stop at failed migration and keep applied versions", func(t *testing.T) {
+		db := setup(t)
+
+		applied, err := Migrate(t.Context(), db, "broken", fstest.MapFS{
+			"0001_init.up.sql":   {Data: []byte("CREATE TABLE broken (id int)")},
+			"0002_broken.up.sql": {Data: []byte("INSERT INTO missing VALUES (1)")},
+		})
+
+		assert.ErrorContains(t, err, "migrate broken 0002_broken.up.sql")
+		assert.Equal(t, []string{"0001"}, applied)
+
+		var count int
+		require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM schema_migrations WHERE source = 'broken'"))
+		assert.Equal(t, 1, count)
+	})
+}
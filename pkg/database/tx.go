@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// Executor runs statements on either the database or a transaction.
+type Executor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+}
+
+// WithTx carries tx in ctx so storages called with it join the transaction.
+func WithTx(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFrom returns the transaction carried by ctx.
+func TxFrom(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx, ok
+}
+
+// Conn returns the transaction carried by ctx, or db outside a transaction.
+func Conn(ctx context.Context, db *sqlx.DB) Executor {
+	if tx, ok := TxFrom(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// ForUpdate is the clause locking the rows a read inside the transaction of ctx selects, so a
+// check and the write depending on it cannot interleave with another transaction. It is empty
+// outside a transaction and for SQLite, which locks the whole database on write instead.
+func ForUpdate(ctx context.Context, db *sqlx.DB) string {
+	if _, ok := TxFrom(ctx); !ok || db.DriverName() == "sqlite" {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// InTx runs fn with a ctx carrying a transaction, committing only when fn succeeds. Inside an
+// ambient transaction fn joins it and the outermost call decides the outcome.
+func InTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) error {
+	if _, ok := TxFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(WithTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Transactor runs units of work on db for services that should not depend on sqlx.
+type Transactor struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return InTx(ctx, t.db, fn)
+}
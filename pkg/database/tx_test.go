@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInTx(t *testing.T) {
+	setup := func(t *testing.T) *sqlx.DB {
+		db, err := sqlx.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		_, err = db.Exec("CREATE TABLE mock_data (id int PRIMARY KEY)")
+		require.NoError(t, err)
+		return db
+	}
+	insert := func(ctx context.Context, db *sqlx.DB, id int) error {
+		_, err := Conn(ctx, db).ExecContext(ctx, "INSERT INTO mock_data (id) VALUES (?)", id)
+		return err
+	}
+	count := func(t *testing.T, db *sqlx.DB) int {
+		var n int
+		require.NoError(t, db.GetContext(t.Context(), &n, "SELECT COUNT(*) FROM mock_data"))
+		return n
+	}
+
+	t.Run("should commit when fn succeeds", func(t *testing.T) {
+		db := setup(t)
+
+		err := NewTransactor(db).InTx(t.Context(), func(ctx context.Context) error {
+			_, ok := TxFrom(ctx)
+			assert.True(t, ok)
+			return insert(ctx, db, 1)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count(t, db))
+	})
+
+	t.Run("should rollback when fn fails", func(t *testing.T) {
+		db := setup(t)
+
+		err := InTx(t.Context(), db, func(ctx context.Context) error {
+			require.NoError(t, insert(ctx, db, 1))
+			return errors.New("fn err")
+		})
+
+		assert.EqualError(t, err, "fn err")
+		assert.Equal(t, 0, count(t, db))
+	})
+
+	t.Run("should rollback and repanic when fn panics", func(t *testing.T) {
+		db := setup(t)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = InTx(t.Context(), db, func(ctx context.Context) error {
+				require.NoError(t, insert(ctx, db, 1))
+				panic("boom")
+			})
+		})
+		assert.Equal(t, 0, count(t, db))
+	})
+
+	t.Run("should join the ambient transaction", func(t *testing.T) {
+		db := setup(t)
+
+		err := InTx(t.Context(), db, func(ctx context.Context) error {
+			outer, _ := TxFrom(ctx)
+			err := InTx(ctx, db, func(ctx context.Context) error {
+				inner, _ := TxFrom(ctx)
+				assert.Same(t, outer, inner)
+				return insert(ctx, db, 1)
+			})
+			require.NoError(t, err)
+			return errors.New("outer err")
+		})
+
+		assert.EqualError(t, err, "outer err")
+		assert.Equal(t, 0, count(t, db))
+	})
+
+	t.Run("should use db outside a transaction", func(t *testing.T) {
+		db := setup(t)
+
+		assert.Same(t, db, Conn(t.Context(), db))
+		require.NoError(t, insert(t.Context(), db, 1))
+		assert.Equal(t, 1, count(t, db))
+	})
+}
+
+func TestForUpdate(t *testing.T) {
+	mysql := sqlx.NewDb(nil, "mysql")
+	tx := &sqlx.Tx{}
+
+	assert.Equal(t, "", ForUpdate(t.Context(), mysql))
+	assert.Equal(t, " FOR UPDATE", ForUpdate(WithTx(t.Context(), tx), mysql))
+	assert.Equal(t, "", ForUpdate(WithTx(t.Context(), tx), sqlx.NewDb(nil, "sqlite")))
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kongsakchai/gotemplate/pkg/httpclient"
+	redis "github.com/redis/go-redis/v9"
+)
+
+type checkFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c checkFunc) Name() string                    { return c.name }
+func (c checkFunc) Check(ctx context.Context) error { return c.check(ctx) }
+
+// NewChecker adapts check into a Checker named name.
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return checkFunc{name: name, check: check}
+}
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DB checks a database connection such as *sqlx.DB or *sql.DB.
+func DB(name string, db Pinger) Checker {
+	return NewChecker(name, db.PingContext)
+}
+
+type RedisPinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}
+
+// Redis checks a go-redis client with PING.
+func Redis(name string, client RedisPinger) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+}
+
+// HTTP checks a downstream dependency by expecting a non error status from GET url.
+func HTTP(name string, client *httpclient.Client, url string) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		resp, err := httpclient.Get[string](ctx, client, url)
+		if err != nil {
+			return err
+		}
+		if resp.Code >= http.StatusBadRequest {
+			return fmt.Errorf("unexpected status %d", resp.Code)
+		}
+		return nil
+	})
+}
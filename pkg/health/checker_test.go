@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kongsakchai/gotemplate/pkg/httpclient"
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) PingContext(context.Context) error { return p.err }
+
+type fakeRedis struct{ err error }
+
+func (r fakeRedis) Ping(ctx context.Context) *redis.StatusCmd {
+	cmd := redis.NewStatusCmd(ctx, "ping")
+	cmd.SetErr(r.err)
+	return cmd
+}
+
+func TestDB(t *testing.T) {
+	assert.Equal(t, "db", DB("db", fakePinger{}).Name())
+	assert.NoError(t, DB("db", fakePinger{}).Check(t.Context()))
+	assert.Error(t, DB("db", fakePinger{err: errors.New("down")}).Check(t.Context()))
+}
+
+func TestRedis(t *testing.T) {
+	assert.NoError(t, Redis("redis", fakeRedis{}).Check(t.Context()))
+	assert.Error(t, Redis("redis", fakeRedis{err: errors.New("down")}).Check(t.Context()))
+}
+
+func TestHTTP(t *testing.T) {
+	serve := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer serve.Close()
+	client := httpclient.New(httpclient.Config{})
+
+	assert.NoError(t, HTTP("payment", client, serve.URL+"/up").Check(t.Context()))
+	assert.EqualError(t, HTTP("payment", client, serve.URL+"/down").Check(t.Context()), "unexpected status 503")
+}
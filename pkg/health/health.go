@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultTimeout = 2 * time.Second
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status    Status    `json:"status"`
+	Checks    []Result  `json:"checks,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Build     any       `json:"build,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+func (r Report) IsUp() bool {
+	return r.Status == StatusUp
+}
+
+type Config struct {
+	Timeout  time.Duration // per check
+	CacheTTL time.Duration // zero disables caching
+	Build    any           // attached to every report, e.g. buildinfo.Info
+}
+
+type Health struct {
+	cfg Config
+
+	mu       sync.RWMutex
+	checkers []Checker
+
+	runMu    sync.Mutex
+	cached   Report
+	cachedAt time.Time
+
+	shutdown atomic.Bool
+}
+
+func New(cfg Config) *Health {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Health{cfg: cfg}
+}
+
+func (h *Health) Register(checkers ...Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, checkers...)
+}
+
+// Shutdown marks the service as not ready so load balancers stop routing before the server closes.
+func (h *Health) Shutdown() {
+	h.shutdown.Store(true)
+}
+
+// Live reports whether the process is able to serve, dependencies are not checked.
+func (h *Health) Live() Report {
+	return Report{Status: StatusUp, Build: h.cfg.Build, CheckedAt: time.Now()}
+}
+
+// Ready runs all checkers, reusing the last report while it is younger than CacheTTL. The report
+// is shared by every probe, so the checks ignore the cancellation of the caller's ctx and are only
+// bounded by Timeout.
+func (h *Health) Ready(ctx context.Context) Report {
+	if h.shutdown.Load() {
+		return Report{Status: StatusDown, Message: "shutting down", Build: h.cfg.Build, CheckedAt: time.Now()}
+	}
+
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+
+	if h.cfg.CacheTTL > 0 && !h.cachedAt.IsZero() && time.Since(h.cachedAt) < h.cfg.CacheTTL {
+		return h.cached
+	}
+
+	report := h.run(context.WithoutCancel(ctx))
+	h.cached, h.cachedAt = report, time.Now()
+	return report
+}
+
+func (h *Health) run(ctx context.Context) Report {
+	h.mu.RLock()
+	checkers := append([]Checker(nil), h.checkers...)
+	h.mu.RUnlock()
+
+	results := make([]Result, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Go(func() {
+			results[i] = h.check(ctx, c)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results, Build: h.cfg.Build, CheckedAt: time.Now()}
+	for _, r := range results {
+		if r.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (h *Health) check(ctx context.Context, c Checker) Result {
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+	defer cancel()
+
+	now := time.Now()
+	errc := make(chan error, 1)
+	go func() { errc <- c.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{Name: c.Name(), Status: StatusUp, Latency: time.Since(now).String()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("should be live without running checks", func(t *testing.T) {
+		h := New(Config{})
+		h.Register(NewChecker("db", func(context.Context) error { return errors.New("down") }))
+
+		assert.True(t, h.Live().IsUp())
+	})
+
+	t.Run("should attach build info to every report", func(t *testing.T) {
+		h := New(Config{Build: "v1.0.0"})
+
+		assert.Equal(t, "v1.0.0", h.Live().Build)
+		assert.Equal(t, "v1.0.0", h.Ready(t.Context()).Build)
+		h.Shutdown()
+		assert.Equal(t, "v1.0.0", h.Ready(t.Context()).Build)
+	})
+
+	t.Run("should report every check", func(t *testing.T) {
+		h := New(Config{})
+		h.Register(
+			NewChecker("db", func(context.Context) error { return nil }),
+			NewChecker("redis", func(context.Context) error { return errors.New("connection refused") }),
+		)
+
+		report := h.Ready(t.Context())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, "db", report.Checks[0].Name)
+		assert.Equal(t, StatusUp, report.Checks[0].Status)
+		assert.NotEmpty(t, report.Checks[0].Latency)
+		assert.Equal(t, "redis", report.Checks[1].Name)
+		assert.Equal(t, StatusDown, report.Checks[1].Status)
+		assert.Equal(t, "connection refused", report.Checks[1].Error)
+	})
+
+	t.Run("should time out slow check", func(t *testing.T) {
+		h := New(Config{Timeout: 10 * time.Millisecond})
+		h.Register(NewChecker("slow", func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}))
+
+		now := time.Now()
+		report := h.Ready(t.Context())
+
+		assert.Less(t, time.Since(now), 500*time.Millisecond)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+	})
+
+	t.Run("should run checks concurrently", func(t *testing.T) {
+		h := New(Config{})
+		for _, name := range []string{"a", "b", "c"} {
+			h.Register(NewChecker(name, func(context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			}))
+		}
+
+		now := time.Now()
+		report := h.Ready(t.Context())
+
+		assert.True(t, report.IsUp())
+		assert.Less(t, time.Since(now), 140*time.Millisecond)
+	})
+
+	t.Run("should cache report", func(t *testing.T) {
+		var calls atomic.Int32
+		h := New(Config{CacheTTL: time.Minute})
+		h.Register(NewChecker("db", func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}))
+
+		h.Ready(t.Context())
+		h.Ready(t.Context())
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should not cache a report cut short by the caller", func(t *testing.T) {
+		var calls atomic.Int32
+		h := New(Config{CacheTTL: time.Minute})
+		h.Register(NewChecker("db", func(ctx context.Context) error {
+			calls.Add(1)
+			return ctx.Err()
+		}))
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		assert.True(t, h.Ready(ctx).IsUp())
+		assert.True(t, h.Ready(t.Context()).IsUp())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should not cache when ttl is zero", func(t *testing.T) {
+		var calls atomic.Int32
+		h := New(Config{})
+		h.Register(NewChecker("db", func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}))
+
+		h.Ready(t.Context())
+		h.Ready(t.Context())
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should not be ready after shutdown", func(t *testing.T) {
+		h := New(Config{CacheTTL: time.Minute})
+		h.Register(NewChecker("db", func(context.Context) error { return nil }))
+		assert.True(t, h.Ready(t.Context()).IsUp())
+
+		h.Shutdown()
+
+		report := h.Ready(t.Context())
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, "shutting down", report.Message)
+		assert.True(t, h.Live().IsUp())
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"maps"
 	"net/http"
 	"time"
+
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 )
 
 const (
@@ -163,8 +165,24 @@ func logHTTPResponse(traceID, data string, status int, req *http.Request) {
 	}
 }
 
-func callRequest[Resp any](ctx context.Context, client *Client, mathod, url string, payload any, headers ...http.Header) (response Response[Resp], err error) {
-	req, err := newRequest(ctx, client, http.MethodPost, url, payload, headers...)
+func callRequest[Resp any](ctx context.Context, client *Client, method, url string, payload any, headers ...http.Header) (response Response[Resp], err error) {
+	ctx, span := trace.Start(ctx, "HTTP "+method,
+		trace.WithKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			slog.String("http.request.method", method),
+			slog.String("url.full", url),
+		),
+	)
+	defer func() {
+		span.SetAttributes(slog.Int("http.response.status_code", response.Code))
+		span.RecordError(err)
+		if err == nil && response.Code >= http.StatusInternalServerError {
+			span.SetStatus(trace.StatusError, http.StatusText(response.Code))
+		}
+		span.End()
+	}()
+
+	req, err := newRequest(ctx, client, method, url, payload, headers...)
 	if err != nil {
 		return response, err
 	}
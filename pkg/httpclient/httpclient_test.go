@@ -274,31 +274,36 @@ func TestCallRequest(t *testing.T) {
 
 func TestMethod(t *testing.T) {
 	type testcase struct {
-		title string
-		call  func(ctx context.Context, client *Client, url string) (Response[string], error)
+		title  string
+		method string
+		call   func(ctx context.Context, client *Client, url string) (Response[string], error)
 	}
 
 	testcases := []testcase{
 		{
-			title: "call method get",
+			title:  "call method get",
+			method: http.MethodGet,
 			call: func(ctx context.Context, client *Client, url string) (Response[string], error) {
 				return Get[string](ctx, client, url)
 			},
 		},
 		{
-			title: "call method post",
+			title:  "call method post",
+			method: http.MethodPost,
 			call: func(ctx context.Context, client *Client, url string) (Response[string], error) {
 				return Post[string](ctx, client, url, nil)
 			},
 		},
 		{
-			title: "call method put",
+			title:  "call method put",
+			method: http.MethodPut,
 			call: func(ctx context.Context, client *Client, url string) (Response[string], error) {
 				return Put[string](ctx, client, url, nil)
 			},
 		},
 		{
-			title: "call method delete",
+			title:  "call method delete",
+			method: http.MethodDelete,
 			call: func(ctx context.Context, client *Client, url string) (Response[string], error) {
 				return Delete[string](ctx, client, url, nil)
 			},
@@ -307,7 +312,9 @@ func TestMethod(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.title, func(t *testing.T) {
+			var method string
 			serve := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
 				w.WriteHeader(http.StatusOK)
 				w.Write([]byte("success"))
 			}))
@@ -322,6 +329,7 @@ func TestMethod(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, http.StatusOK, resp.Code)
 			assert.Equal(t, "success", resp.Data)
+			assert.Equal(t, tc.method, method, "sends its own method, not always POST")
 		})
 	}
 }
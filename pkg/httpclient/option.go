@@ -3,14 +3,21 @@ package httpclient
 import (
 	"context"
 	"net/http"
+
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 )
 
 type OptionFunc func(*http.Request, context.Context) context.Context
 
+// TraceOption forwards the ref ID stored under key and the traceparent of the current client span.
 func TraceOption(key string) OptionFunc {
 	return func(r *http.Request, ctx context.Context) context.Context {
-		trace, _ := ctx.Value(key).(string)
-		r.Header.Set(key, trace)
+		refID, _ := ctx.Value(key).(string)
+		r.Header.Set(key, refID)
+
+		if sc, ok := trace.FromContext(ctx); ok {
+			trace.Inject(r.Header, sc)
+		}
 
 		return ctx
 	}
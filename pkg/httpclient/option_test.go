@@ -3,8 +3,10 @@ package httpclient
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/kongsakchai/gotemplate/pkg/trace"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,3 +22,44 @@ func TestTraceOption(t *testing.T) {
 		assert.Equal(t, req.Header.Get(key), value)
 	})
 }
+
+func TestTraceOptionTraceParent(t *testing.T) {
+	t.Run("should forward current traceparent", func(t *testing.T) {
+		sc := trace.NewSpanContext()
+		sc.State = "vendor=value"
+		ctx := trace.ContextWith(context.Background(), sc)
+
+		c := New(Config{}, TraceOption("ref"))
+		req, err := newRequest(ctx, c, http.MethodGet, "", nil)
+		assert.NoError(t, err)
+
+		got, ok := trace.Extract(req.Header)
+		assert.True(t, ok)
+		assert.Equal(t, sc, got)
+	})
+
+	t.Run("should forward client span as parent", func(t *testing.T) {
+		var got trace.SpanContext
+		serve := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = trace.Extract(r.Header)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer serve.Close()
+
+		sc := trace.NewSpanContext()
+		c := New(Config{}, TraceOption("ref"))
+		_, err := Get[string](trace.ContextWith(context.Background(), sc), c, serve.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sc.TraceID, got.TraceID)
+		assert.NotEqual(t, sc.SpanID, got.SpanID)
+	})
+
+	t.Run("should not set traceparent without span context", func(t *testing.T) {
+		c := New(Config{}, TraceOption("ref"))
+		req, err := newRequest(context.Background(), c, http.MethodGet, "", nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, req.Header.Get(trace.HeaderTraceParent))
+	})
+}
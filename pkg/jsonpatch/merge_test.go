@@ -0,0 +1,53 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	// cases from RFC 7396 appendix A
+	cases := []struct {
+		name, doc, patch, want string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"remove member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"remove one of two", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"replace array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"array replaces value", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are not merged", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"non object patch", `{"a":"foo"}`, `["c"]`, `["c"]`},
+		{"null patch", `{"a":"foo"}`, `null`, `null`},
+		{"non object target", `["a","b"]`, `{"a":"b"}`, `{"a":"b"}`},
+		{"nested null into empty", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"keep large numbers", `{"n":12345678901234567890}`, `{"a":1}`, `{"a":1,"n":12345678901234567890}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(got))
+		})
+	}
+
+	t.Run("should reject invalid patch", func(t *testing.T) {
+		_, err := MergePatch([]byte(`{}`), []byte(`{`))
+		assert.ErrorIs(t, err, ErrInvalidPatch)
+	})
+
+	t.Run("should reject trailing data", func(t *testing.T) {
+		_, err := MergePatch([]byte(`{}`), []byte(`{} {}`))
+		assert.ErrorIs(t, err, ErrInvalidPatch)
+	})
+
+	t.Run("should reject invalid document", func(t *testing.T) {
+		_, err := MergePatch([]byte(`{`), []byte(`{}`))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidPatch)
+	})
+}
@@ -0,0 +1,303 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch = errors.New("jsonpatch: invalid patch")
+	ErrPathNotFound = errors.New("jsonpatch: path not found")
+	ErrTestFailed   = errors.New("jsonpatch: test failed")
+)
+
+// Operation is one RFC 6902 operation.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type Patch []Operation
+
+// Decode parses an RFC 6902 document, it does not check the operations until Apply.
+func Decode(b []byte) (Patch, error) {
+	var p Patch
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	return p, nil
+}
+
+// Apply runs the operations in order and fails without a partial result when any of them fails.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	var root any
+	if err := unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("jsonpatch: document: %w", err)
+	}
+
+	for i, op := range p {
+		var err error
+		if root, err = op.apply(root); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func (op Operation) value() (any, error) {
+	if len(op.Value) == 0 {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+	}
+	var v any
+	if err := unmarshal(op.Value, &v); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	return v, nil
+}
+
+func (op Operation) apply(root any) (any, error) {
+	switch op.Op {
+	case "add":
+		v, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "remove":
+		_, root, err := remove(root, op.Path)
+		return root, err
+	case "replace":
+		v, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, root, err = remove(root, op.Path); err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "move":
+		if strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+			return nil, fmt.Errorf("%w: cannot move into own child", ErrInvalidPatch)
+		}
+		v, root, err := remove(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "copy":
+		v, err := get(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, deepCopy(v))
+	case "test":
+		want, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(got, want) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits an RFC 6901 pointer into unescaped tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: invalid index %q", ErrInvalidPatch, token)
+	}
+	if i > length || (!allowEnd && i == length) {
+		return 0, fmt.Errorf("%w: index %d", ErrPathNotFound, i)
+	}
+	return i, nil
+}
+
+func get(root any, path string) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := root
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			cur = v
+		case []any:
+			i, err := arrayIndex(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+	}
+	return cur, nil
+}
+
+// update replaces the container addressed by the parent of path with fn's result.
+func update(root any, path string, fn func(parent any, last string) (any, error)) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return fn(nil, "")
+	}
+
+	var walk func(cur any, tokens []string) (any, error)
+	walk = func(cur any, tokens []string) (any, error) {
+		if len(tokens) == 1 {
+			return fn(cur, tokens[0])
+		}
+		switch c := cur.(type) {
+		case map[string]any:
+			child, ok := c[tokens[0]]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			v, err := walk(child, tokens[1:])
+			if err != nil {
+				return nil, err
+			}
+			c[tokens[0]] = v
+			return c, nil
+		case []any:
+			i, err := arrayIndex(tokens[0], len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			v, err := walk(c[i], tokens[1:])
+			if err != nil {
+				return nil, err
+			}
+			c[i] = v
+			return c, nil
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+	}
+	return walk(root, tokens)
+}
+
+func add(root any, path string, value any) (any, error) {
+	return update(root, path, func(parent any, last string) (any, error) {
+		switch c := parent.(type) {
+		case nil:
+			if path == "" {
+				return value, nil
+			}
+		case map[string]any:
+			c[last] = value
+			return c, nil
+		case []any:
+			i, err := arrayIndex(last, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	})
+}
+
+func remove(root any, path string) (any, any, error) {
+	if path == "" {
+		return root, nil, nil
+	}
+
+	var removed any
+	root, err := update(root, path, func(parent any, last string) (any, error) {
+		switch c := parent.(type) {
+		case map[string]any:
+			v, ok := c[last]
+			if !ok {
+				break
+			}
+			removed = v
+			delete(c, last)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(last, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	})
+	return removed, root, err
+}
+
+func deepCopy(v any) any {
+	switch c := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(c))
+		for k, v := range c {
+			m[k] = deepCopy(v)
+		}
+		return m
+	case []any:
+		s := make([]any, len(c))
+		for i, v := range c {
+			s[i] = deepCopy(v)
+		}
+		return s
+	}
+	return v
+}
+
+// equal compares numbers by value, so 1 and 1.0 are equal as RFC 6902 requires.
+func equal(a, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		if aerr == nil && berr == nil {
+			return af == bf
+		}
+		return an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,174 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 15 * time.Second
+
+// Hook is a named resource or module started and stopped by the Lifecycle.
+// Either func may be nil, Timeout applies to each of them separately.
+type Hook struct {
+	Name      string
+	DependsOn []string
+	Timeout   time.Duration
+	OnStart   func(ctx context.Context) error
+	OnStop    func(ctx context.Context) error
+}
+
+type Lifecycle struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started []Hook
+}
+
+func New(logger *slog.Logger) *Lifecycle {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Lifecycle{logger: logger}
+}
+
+func (l *Lifecycle) Append(hooks ...Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hooks...)
+}
+
+// Start runs OnStart of every hook after its dependencies, in registration order otherwise.
+// When a hook fails the hooks already started are stopped in reverse order and the error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ordered, err := order(l.hooks)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range ordered {
+		if err := ctx.Err(); err != nil {
+			l.rollback()
+			return err
+		}
+
+		now := time.Now()
+		if err := run(ctx, h, h.OnStart); err != nil {
+			l.logger.ErrorContext(ctx, "start "+h.Name+" fail", "err", err.Error())
+			l.rollback()
+			return fmt.Errorf("start %s: %w", h.Name, err)
+		}
+		l.logger.InfoContext(ctx, "started "+h.Name, "latency", time.Since(now).String())
+		l.started = append(l.started, h)
+	}
+	return nil
+}
+
+// Stop runs OnStop of the started hooks in reverse order, ctx bounds the whole shutdown.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) rollback() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	_ = l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		h := l.started[i]
+
+		now := time.Now()
+		if err := run(ctx, h, h.OnStop); err != nil {
+			l.logger.ErrorContext(ctx, "stop "+h.Name+" fail", "err", err.Error())
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+		l.logger.InfoContext(ctx, "stopped "+h.Name, "latency", time.Since(now).String())
+	}
+	l.started = nil
+	return errors.Join(errs...)
+}
+
+func run(ctx context.Context, h Hook, fn func(context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() { errc <- fn(ctx) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// order sorts hooks so each one comes after its dependencies, keeping registration order otherwise.
+func order(hooks []Hook) ([]Hook, error) {
+	byName := make(map[string]Hook, len(hooks))
+	for _, h := range hooks {
+		if _, ok := byName[h.Name]; ok {
+			return nil, fmt.Errorf("lifecycle: duplicate hook %q", h.Name)
+		}
+		byName[h.Name] = h
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(hooks))
+	ordered := make([]Hook, 0, len(hooks))
+
+	var visit func(h Hook, path []string) error
+	visit = func(h Hook, path []string) error {
+		switch state[h.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("lifecycle: dependency cycle %v", append(path, h.Name))
+		}
+
+		state[h.Name] = visiting
+		for _, dep := range h.DependsOn {
+			d, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("lifecycle: %q depends on unknown hook %q", h.Name, dep)
+			}
+			if err := visit(d, append(path, h.Name)); err != nil {
+				return err
+			}
+		}
+		state[h.Name] = visited
+		ordered = append(ordered, h)
+		return nil
+	}
+
+	for _, h := range hooks {
+		if err := visit(h, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) hook(name string, deps ...string) Hook {
+	return Hook{
+		Name:      name,
+		DependsOn: deps,
+		OnStart: func(context.Context) error {
+			r.calls = append(r.calls, "start "+name)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func newLifecycle() *Lifecycle {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Run("should start in dependency order and stop in reverse", func(t *testing.T) {
+		r := &recorder{}
+		l := newLifecycle()
+		l.Append(
+			r.hook("http", "member", "database"),
+			r.hook("member", "database", "redis"),
+			r.hook("database"),
+			r.hook("redis"),
+		)
+
+		assert.NoError(t, l.Start(t.Context()))
+		assert.NoError(t, l.Stop(t.Context()))
+
+		assert.Equal(t, []string{
+			"start database", "start redis", "start member", "start http",
+			"stop http", "stop member", "stop redis", "stop database",
+		}, r.calls)
+	})
+
+	t.Run("should roll back started hooks when start fails", func(t *testing.T) {
+		r := &recorder{}
+		l := newLifecycle()
+		broken := r.hook("redis")
+		broken.OnStart = func(context.Context) error { return errors.New("connection refused") }
+		l.Append(r.hook("database"), broken, r.hook("http", "redis"))
+
+		err := l.Start(t.Context())
+
+		assert.EqualError(t, err, "start redis: connection refused")
+		assert.Equal(t, []string{"start database", "stop database"}, r.calls)
+		assert.NoError(t, l.Stop(t.Context()))
+		assert.Len(t, r.calls, 2)
+	})
+
+	t.Run("should time out slow hook", func(t *testing.T) {
+		l := newLifecycle()
+		l.Append(Hook{
+			Name:    "slow",
+			Timeout: 10 * time.Millisecond,
+			OnStart: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		})
+
+		err := l.Start(t.Context())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should keep stopping after a hook fails", func(t *testing.T) {
+		r := &recorder{}
+		l := newLifecycle()
+		broken := r.hook("redis")
+		broken.OnStop = func(context.Context) error { return errors.New("close fail") }
+		l.Append(r.hook("database"), broken)
+
+		assert.NoError(t, l.Start(t.Context()))
+		err := l.Stop(t.Context())
+
+		assert.EqualError(t, err, "stop redis: close fail")
+		assert.Contains(t, r.calls, "stop database")
+	})
+
+	t.Run("should skip nil funcs", func(t *testing.T) {
+		l := newLifecycle()
+		l.Append(Hook{Name: "noop"})
+
+		assert.NoError(t, l.Start(t.Context()))
+		assert.NoError(t, l.Stop(t.Context()))
+	})
+
+	t.Run("should not start when context is done", func(t *testing.T) {
+		r := &recorder{}
+		l := newLifecycle()
+		l.Append(r.hook("database"))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		assert.ErrorIs(t, l.Start(ctx), context.Canceled)
+		assert.Empty(t, r.calls)
+	})
+}
+
+func TestOrder(t *testing.T) {
+	t.Run("should fail on unknown dependency", func(t *testing.T) {
+		_, err := order([]Hook{{Name: "http", DependsOn: []string{"db"}}})
+		assert.EqualError(t, err, `lifecycle: "http" depends on unknown hook "db"`)
+	})
+
+	t.Run("should fail on cycle", func(t *testing.T) {
+		_, err := order([]Hook{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+		})
+		assert.EqualError(t, err, "lifecycle: dependency cycle [a b a]")
+	})
+
+	t.Run("should fail on duplicate name", func(t *testing.T) {
+		_, err := order([]Hook{{Name: "a"}, {Name: "a"}})
+		assert.EqualError(t, err, `lifecycle: duplicate hook "a"`)
+	})
+}
@@ -0,0 +1,97 @@
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Initial int
+	Min     int
+	Max     int
+	// Threshold is the latency above which a request counts as a sign of overload.
+	Threshold time.Duration
+	// Backoff multiplies the limit on overload, e.g. 0.9.
+	Backoff float64
+}
+
+// AIMD caps concurrent work with a limit that grows by one while requests are fast and the
+// limit is in use, and shrinks multiplicatively when a request is slow or dropped.
+type AIMD struct {
+	cfg Config
+
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+}
+
+func NewAIMD(cfg Config) *AIMD {
+	cfg.Min = max(cfg.Min, 1)
+	if cfg.Max < cfg.Min {
+		cfg.Max = math.MaxInt
+	}
+	if cfg.Backoff <= 0 || cfg.Backoff >= 1 {
+		cfg.Backoff = 0.9
+	}
+	return &AIMD{cfg: cfg, limit: min(max(cfg.Initial, cfg.Min), cfg.Max)}
+}
+
+// Acquire reserves a slot, a false result means the caller should shed the request.
+// Every successful Acquire must be followed by one Release.
+func (l *AIMD) Acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= l.limit {
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+// Release frees the slot and adapts the limit to the observed latency.
+func (l *AIMD) Release(latency time.Duration, dropped bool) {
+	l.ReleaseWithin(latency, l.cfg.Threshold, dropped)
+}
+
+// ReleaseWithin is Release against the threshold of the work done, e.g. a slower route.
+func (l *AIMD) ReleaseWithin(latency, threshold time.Duration, dropped bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+
+	switch {
+	case dropped || (threshold > 0 && latency > threshold):
+		l.limit = max(int(float64(l.limit)*l.cfg.Backoff), l.cfg.Min)
+	case inFlight*2 >= l.limit:
+		// only grow while the limit is actually used, idle services keep their limit
+		l.limit = min(l.limit+1, l.cfg.Max)
+	}
+}
+
+// Skip frees the slot without reading the latency, for long running or streaming work whose
+// duration says nothing about overload. A dropped request still backs off.
+func (l *AIMD) Skip(dropped bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if dropped {
+		l.limit = max(int(float64(l.limit)*l.cfg.Backoff), l.cfg.Min)
+	}
+}
+
+func (l *AIMD) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+func (l *AIMD) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
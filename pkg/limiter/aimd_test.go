@@ -0,0 +1,92 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAIMD(t *testing.T) {
+	t.Run("should apply defaults", func(t *testing.T) {
+		l := NewAIMD(Config{})
+
+		assert.Equal(t, 1, l.Limit())
+		assert.Equal(t, 0.9, l.cfg.Backoff)
+	})
+
+	t.Run("should reject when limit reached", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 2, Min: 1, Max: 10})
+
+		assert.True(t, l.Acquire())
+		assert.True(t, l.Acquire())
+		assert.False(t, l.Acquire())
+		assert.Equal(t, 2, l.InFlight())
+
+		l.Release(time.Millisecond, false)
+		assert.True(t, l.Acquire())
+	})
+
+	t.Run("should increase limit while fast and in use", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 2, Min: 1, Max: 3, Threshold: time.Second})
+
+		l.Acquire()
+		l.Release(time.Millisecond, false)
+		assert.Equal(t, 3, l.Limit())
+
+		l.Acquire()
+		l.Acquire()
+		l.Release(time.Millisecond, false)
+		assert.Equal(t, 3, l.Limit(), "capped at max")
+	})
+
+	t.Run("should keep limit when mostly idle", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 10, Max: 100, Threshold: time.Second})
+
+		l.Acquire()
+		l.Release(time.Millisecond, false)
+		assert.Equal(t, 10, l.Limit())
+	})
+
+	t.Run("should back off when slow", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 10, Min: 8, Max: 100, Threshold: time.Second, Backoff: 0.5})
+
+		l.Acquire()
+		l.Release(2*time.Second, false)
+		assert.Equal(t, 8, l.Limit(), "floored at min")
+	})
+
+	t.Run("should use the given threshold", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 10, Min: 1, Max: 100, Threshold: time.Second, Backoff: 0.5})
+
+		l.Acquire()
+		l.ReleaseWithin(2*time.Second, time.Minute, false)
+		assert.Equal(t, 10, l.Limit())
+
+		l.Acquire()
+		l.ReleaseWithin(2*time.Minute, time.Minute, false)
+		assert.Equal(t, 5, l.Limit())
+	})
+
+	t.Run("should keep limit when skipped", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 2, Min: 1, Max: 100, Threshold: time.Second, Backoff: 0.5})
+
+		l.Acquire()
+		l.Acquire()
+		l.Skip(false)
+		assert.Equal(t, 2, l.Limit())
+		assert.Equal(t, 1, l.InFlight())
+
+		l.Skip(true)
+		assert.Equal(t, 1, l.Limit())
+		assert.Equal(t, 0, l.InFlight())
+	})
+
+	t.Run("should back off when dropped", func(t *testing.T) {
+		l := NewAIMD(Config{Initial: 10, Max: 100, Backoff: 0.5})
+
+		l.Acquire()
+		l.Release(time.Millisecond, true)
+		assert.Equal(t, 5, l.Limit())
+	})
+}
@@ -1,31 +1,48 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
 	"github.com/kongsakchai/paint"
 )
 
-var logLevel slog.Level
+const disabledLevel slog.Level = 99
+
+// logLevel is shared by every logger from New so the level can change at runtime.
+var logLevel = new(slog.LevelVar)
 
 func SetLevel(level string, enable string) {
 	if enable != "true" {
-		logLevel = 99
+		logLevel.Set(disabledLevel)
 		return
 	}
 
+	logLevel.Set(ParseLevel(level))
+}
+
+func Level() slog.Level {
+	return logLevel.Level()
+}
+
+// UseLevel changes the level of every logger created by New.
+func UseLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+func ParseLevel(level string) slog.Level {
 	switch level {
 	case "debug":
-		logLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case "info":
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	case "warn":
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo // Default to Info level if an unknown level is provided
+		return slog.LevelInfo // Default to Info level if an unknown level is provided
 	}
 }
 
@@ -38,11 +55,14 @@ type ReplaceFunc func(groups []string, a slog.Attr) (slog.Attr, bool)
 func New(replaceAttrs ...ReplaceFunc) *slog.Logger {
 	var handler slog.Handler
 	if os.Getenv("LOG_FORMAT") == "text" {
-		handler = paint.NewTextHandler(os.Stdout, &paint.HandlerOptions{
-			Level:       logLevel,
-			ReplaceAttr: newReplaceFuncGroup(replaceAttrs...),
-			TimeFormat:  "[2006/01/02 15:04:05]",
-		})
+		handler = &levelHandler{
+			Handler: paint.NewTextHandler(os.Stdout, &paint.HandlerOptions{
+				Level:       slog.LevelDebug - 4, // filtered by levelHandler
+				ReplaceAttr: newReplaceFuncGroup(replaceAttrs...),
+				TimeFormat:  "[2006/01/02 15:04:05]",
+			}),
+			level: logLevel,
+		}
 	} else {
 		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 			Level:       logLevel,
@@ -66,3 +86,21 @@ func newReplaceFuncGroup(replaceAttrs ...ReplaceFunc) func(groups []string, a sl
 		return a
 	}
 }
+
+// levelHandler applies a dynamic level to handlers that only accept a fixed one.
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func resetLogLevel(defaultLevel slog.Level) {
-	logLevel = defaultLevel
+	logLevel.Set(defaultLevel)
 }
 
 func TestSetLevel(t *testing.T) {
@@ -28,15 +28,35 @@ func TestSetLevel(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.title, func(t *testing.T) {
-			defaultLevel := logLevel
+			defaultLevel := logLevel.Level()
 			defer resetLogLevel(defaultLevel)
 
 			SetLevel(tc.level, tc.enable)
-			assert.Equal(t, tc.want, logLevel)
+			assert.Equal(t, tc.want, logLevel.Level())
 		})
 	}
 }
 
+func TestUseLevel(t *testing.T) {
+	t.Run("should change level of existing loggers", func(t *testing.T) {
+		defaultLogger := slog.Default()
+		defer resetLogger(defaultLogger)
+		defer resetLogLevel(Level())
+
+		for _, format := range []string{"text", "json"} {
+			t.Setenv("LOG_FORMAT", format)
+			UseLevel(slog.LevelInfo)
+			logger := New().With("key", "value").WithGroup("group")
+
+			assert.False(t, logger.Enabled(t.Context(), slog.LevelDebug), format)
+
+			UseLevel(slog.LevelDebug)
+			assert.Equal(t, slog.LevelDebug, Level())
+			assert.True(t, logger.Enabled(t.Context(), slog.LevelDebug), format)
+		}
+	})
+}
+
 func resetLogger(logger *slog.Logger) {
 	slog.SetDefault(logger)
 }
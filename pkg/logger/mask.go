@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"strings"
+)
+
+const maskValue = `"***"`
+
+// MaskJSON replaces the value of every object key listed in fields with "***".
+// Keys are matched case-insensitively. The input is scanned instead of decoded,
+// so truncated or invalid JSON is still masked on a best-effort basis.
+func MaskJSON(data []byte, fields []string) []byte {
+	if len(fields) == 0 || len(data) == 0 {
+		return data
+	}
+
+	keys := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		keys[strings.ToLower(strings.TrimSpace(f))] = struct{}{}
+	}
+
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out = append(out, data[i])
+			i++
+			continue
+		}
+
+		end := scanString(data, i)
+		out = append(out, data[i:end]...)
+		key := data[i:end]
+		i = end
+
+		colon := skipSpace(data, i)
+		if colon >= len(data) || data[colon] != ':' || !isMaskedKey(keys, key) {
+			continue
+		}
+
+		value := skipSpace(data, colon+1)
+		out = append(out, data[i:value]...)
+		out = append(out, maskValue...)
+		i = skipValue(data, value)
+	}
+
+	return out
+}
+
+func isMaskedKey(keys map[string]struct{}, quoted []byte) bool {
+	if len(quoted) < 2 || quoted[len(quoted)-1] != '"' {
+		return false
+	}
+	_, ok := keys[strings.ToLower(string(quoted[1:len(quoted)-1]))]
+	return ok
+}
+
+// scanString returns the index right after the string starting at data[i].
+func scanString(data []byte, i int) int {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(data)
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipValue returns the index right after the JSON value starting at data[i].
+func skipValue(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+
+	switch data[i] {
+	case '"':
+		return scanString(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				j = scanString(data, j) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return len(data)
+	default:
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return j
+			}
+		}
+		return len(data)
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskJSON(t *testing.T) {
+	fields := []string{"password", "Birthday"}
+
+	testcases := []struct {
+		title string
+		data  string
+		want  string
+	}{
+		{"mask string value", `{"username":"john","password":"secret"}`, `{"username":"john","password":"***"}`},
+		{"mask case insensitive", `{"birthday": "2000-01-01"}`, `{"birthday": "***"}`},
+		{"mask number value", `{"password":1234,"a":1}`, `{"password":"***","a":1}`},
+		{"mask object value", `{"password":{"a":"}"},"b":2}`, `{"password":"***","b":2}`},
+		{"mask nested key", `{"user":{"password":"x"}}`, `{"user":{"password":"***"}}`},
+		{"mask inside array", `[{"password":"x"},{"password":null}]`, `[{"password":"***"},{"password":"***"}]`},
+		{"keep string value equal to key", `{"name":"password"}`, `{"name":"password"}`},
+		{"keep escaped quote", `{"name":"a\"password\"","password":"x"}`, `{"name":"a\"password\"","password":"***"}`},
+		{"mask truncated json", `{"username":"john","password":"sec`, `{"username":"john","password":"***"`},
+		{"keep non json", `hello world`, `hello world`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.title, func(t *testing.T) {
+			got := MaskJSON([]byte(tc.data), fields)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+
+	t.Run("should return input when no fields", func(t *testing.T) {
+		data := []byte(`{"password":"x"}`)
+		assert.Equal(t, data, MaskJSON(data, nil))
+	})
+}
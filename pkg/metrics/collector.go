@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"database/sql"
+	"runtime"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+func single(name, help string, typ MetricType, value float64, labels ...Label) Family {
+	return Family{Name: name, Help: help, Type: typ, Samples: []Sample{{Labels: labels, Value: value}}}
+}
+
+func namesOf(families []Family) []string {
+	names := make([]string, len(families))
+	for i, f := range families {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// NewGoCollector exposes goroutine, memory and GC statistics of the Go runtime.
+func NewGoCollector() Collector {
+	collect := func() []Family {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		return []Family{
+			single("go_info", "Information about the Go environment.", TypeGauge, 1, Label{Name: "version", Value: runtime.Version()}),
+			single("go_goroutines", "Number of goroutines that currently exist.", TypeGauge, float64(runtime.NumGoroutine())),
+			single("go_gomaxprocs", "Value of GOMAXPROCS.", TypeGauge, float64(runtime.GOMAXPROCS(0))),
+			single("go_gc_cycles_total", "Number of completed GC cycles.", TypeCounter, float64(mem.NumGC)),
+			single("go_gc_pause_seconds_total", "Cumulative GC stop-the-world pause time in seconds.", TypeCounter, float64(mem.PauseTotalNs)/float64(time.Second)),
+			single("go_memstats_last_gc_time_seconds", "Unix time of the last GC.", TypeGauge, float64(mem.LastGC)/float64(time.Second)),
+			single("go_memstats_alloc_bytes", "Bytes of allocated heap objects.", TypeGauge, float64(mem.Alloc)),
+			single("go_memstats_alloc_bytes_total", "Cumulative bytes allocated for heap objects.", TypeCounter, float64(mem.TotalAlloc)),
+			single("go_memstats_sys_bytes", "Bytes of memory obtained from the OS.", TypeGauge, float64(mem.Sys)),
+			single("go_memstats_mallocs_total", "Cumulative count of heap objects allocated.", TypeCounter, float64(mem.Mallocs)),
+			single("go_memstats_frees_total", "Cumulative count of heap objects freed.", TypeCounter, float64(mem.Frees)),
+			single("go_memstats_heap_inuse_bytes", "Bytes in in-use heap spans.", TypeGauge, float64(mem.HeapInuse)),
+			single("go_memstats_heap_idle_bytes", "Bytes in idle heap spans.", TypeGauge, float64(mem.HeapIdle)),
+			single("go_memstats_heap_released_bytes", "Bytes of physical memory returned to the OS.", TypeGauge, float64(mem.HeapReleased)),
+			single("go_memstats_heap_objects", "Number of allocated heap objects.", TypeGauge, float64(mem.HeapObjects)),
+			single("go_memstats_stack_inuse_bytes", "Bytes in stack spans.", TypeGauge, float64(mem.StackInuse)),
+			single("go_memstats_stack_sys_bytes", "Bytes of stack memory obtained from the OS.", TypeGauge, float64(mem.StackSys)),
+			single("go_memstats_next_gc_bytes", "Target heap size of the next GC cycle.", TypeGauge, float64(mem.NextGC)),
+		}
+	}
+	return NewCollectorFunc(collect, namesOf(collect())...)
+}
+
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+// NewDBStatsCollector exposes the connection pool stats of db labelled with name.
+func NewDBStatsCollector(name string, db DBStatser) Collector {
+	collect := func() []Family {
+		s := db.Stats()
+		l := Label{Name: "db_name", Value: name}
+
+		return []Family{
+			single("go_sql_max_open_connections", "Maximum number of open connections to the database.", TypeGauge, float64(s.MaxOpenConnections), l),
+			single("go_sql_open_connections", "The number of established connections both in use and idle.", TypeGauge, float64(s.OpenConnections), l),
+			single("go_sql_in_use_connections", "The number of connections currently in use.", TypeGauge, float64(s.InUse), l),
+			single("go_sql_idle_connections", "The number of idle connections.", TypeGauge, float64(s.Idle), l),
+			single("go_sql_wait_count_total", "The total number of connections waited for.", TypeCounter, float64(s.WaitCount), l),
+			single("go_sql_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", TypeCounter, s.WaitDuration.Seconds(), l),
+			single("go_sql_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", TypeCounter, float64(s.MaxIdleClosed), l),
+			single("go_sql_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", TypeCounter, float64(s.MaxIdleTimeClosed), l),
+			single("go_sql_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", TypeCounter, float64(s.MaxLifetimeClosed), l),
+		}
+	}
+	return NewCollectorFunc(collect, namesOf(collect())...)
+}
+
+type RedisPoolStatser interface {
+	PoolStats() *redis.PoolStats
+}
+
+// NewRedisCollector exposes the connection pool stats of a go-redis client labelled with name.
+func NewRedisCollector(name string, client RedisPoolStatser) Collector {
+	collect := func() []Family {
+		s := client.PoolStats()
+		l := Label{Name: "client", Value: name}
+
+		return []Family{
+			single("redis_pool_hits_total", "Number of times a free connection was found in the pool.", TypeCounter, float64(s.Hits), l),
+			single("redis_pool_misses_total", "Number of times a free connection was not found in the pool.", TypeCounter, float64(s.Misses), l),
+			single("redis_pool_timeouts_total", "Number of times a wait timeout occurred.", TypeCounter, float64(s.Timeouts), l),
+			single("redis_pool_wait_total", "Number of times a connection was waited for.", TypeCounter, float64(s.WaitCount), l),
+			single("redis_pool_wait_duration_seconds_total", "Total time spent waiting for a connection.", TypeCounter, time.Duration(s.WaitDurationNs).Seconds(), l),
+			single("redis_pool_total_connections", "Number of connections in the pool.", TypeGauge, float64(s.TotalConns), l),
+			single("redis_pool_idle_connections", "Number of idle connections in the pool.", TypeGauge, float64(s.IdleConns), l),
+			single("redis_pool_stale_connections_total", "Number of stale connections removed from the pool.", TypeCounter, float64(s.StaleConns), l),
+		}
+	}
+	return NewCollectorFunc(collect, namesOf(collect())...)
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDB sql.DBStats
+
+func (db fakeDB) Stats() sql.DBStats { return sql.DBStats(db) }
+
+type fakeRedis redis.PoolStats
+
+func (r fakeRedis) PoolStats() *redis.PoolStats {
+	s := redis.PoolStats(r)
+	return &s
+}
+
+func TestGoCollector(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewGoCollector())
+
+	sb := strings.Builder{}
+	require.NoError(t, reg.WriteText(&sb))
+
+	assert.Contains(t, sb.String(), "# TYPE go_goroutines gauge\n")
+	assert.Contains(t, sb.String(), "# TYPE go_gc_cycles_total counter\n")
+	assert.Contains(t, sb.String(), "go_memstats_heap_inuse_bytes ")
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewDBStatsCollector("main", fakeDB{OpenConnections: 4, InUse: 1, Idle: 3, WaitDuration: 1500 * time.Millisecond}))
+
+	sb := strings.Builder{}
+	require.NoError(t, reg.WriteText(&sb))
+
+	assert.Contains(t, sb.String(), `go_sql_open_connections{db_name="main"} 4`)
+	assert.Contains(t, sb.String(), `go_sql_in_use_connections{db_name="main"} 1`)
+	assert.Contains(t, sb.String(), `go_sql_wait_duration_seconds_total{db_name="main"} 1.5`)
+}
+
+func TestRedisCollector(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewRedisCollector("cache", fakeRedis{Hits: 10, Misses: 2, TotalConns: 5, IdleConns: 4}))
+
+	sb := strings.Builder{}
+	require.NoError(t, reg.WriteText(&sb))
+
+	assert.Contains(t, sb.String(), `redis_pool_hits_total{client="cache"} 10`)
+	assert.Contains(t, sb.String(), `redis_pool_misses_total{client="cache"} 2`)
+	assert.Contains(t, sb.String(), `redis_pool_idle_connections{client="cache"} 4`)
+}
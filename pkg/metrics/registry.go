@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type MetricType string
+
+const (
+	TypeCounter   MetricType = "counter"
+	TypeGauge     MetricType = "gauge"
+	TypeHistogram MetricType = "histogram"
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+type Sample struct {
+	Suffix string // e.g. "_bucket", "_sum", "_count"
+	Labels []Label
+	Value  float64
+}
+
+// Family is one metric name with its help, type and current samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector produces metric families on each scrape.
+type Collector interface {
+	Describe() []string
+	Collect() []Family
+}
+
+type Registry struct {
+	mu         sync.RWMutex
+	names      map[string]struct{}
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]struct{}{}}
+}
+
+// Register adds collectors, failing when a metric name is already registered.
+func (r *Registry) Register(cs ...Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := map[string]struct{}{}
+	for _, c := range cs {
+		for _, name := range c.Describe() {
+			if _, ok := r.names[name]; ok {
+				return fmt.Errorf("metrics: %q is already registered", name)
+			}
+			if _, ok := seen[name]; ok {
+				return fmt.Errorf("metrics: %q is registered twice", name)
+			}
+			seen[name] = struct{}{}
+		}
+	}
+
+	for name := range seen {
+		r.names[name] = struct{}{}
+	}
+	r.collectors = append(r.collectors, cs...)
+	return nil
+}
+
+func (r *Registry) MustRegister(cs ...Collector) {
+	if err := r.Register(cs...); err != nil {
+		panic(err)
+	}
+}
+
+// Gather collects all families sorted by name.
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.RUnlock()
+
+	var families []Family
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+	slices.SortStableFunc(families, func(a, b Family) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return families
+}
+
+// WriteText writes all families in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range r.Gather() {
+		writeFamily(bw, f)
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+func writeFamily(w *bufio.Writer, f Family) {
+	if f.Help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.Name, helpReplacer.Replace(f.Help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.Name, f.Type)
+
+	for _, s := range f.Samples {
+		w.WriteString(f.Name + s.Suffix)
+		if len(s.Labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				fmt.Fprintf(w, "%s=\"%s\"", l.Name, labelReplacer.Replace(l.Value))
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatFloat(s.Value))
+		w.WriteByte('\n')
+	}
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("should reject duplicate metric names", func(t *testing.T) {
+		reg := NewRegistry()
+
+		assert.NoError(t, reg.Register(NewCounterVec("a_total", "")))
+		assert.Error(t, reg.Register(NewGaugeVec("a_total", "")))
+		assert.Error(t, reg.Register(NewGaugeVec("b", ""), NewGaugeVec("b", "")))
+		assert.NoError(t, reg.Register(NewGaugeVec("b", "")))
+	})
+
+	t.Run("should panic on must register duplicate", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.MustRegister(NewCounterVec("a_total", ""))
+
+		assert.Panics(t, func() { reg.MustRegister(NewCounterVec("a_total", "")) })
+	})
+
+	t.Run("should write text exposition sorted by name", func(t *testing.T) {
+		reg := NewRegistry()
+		c := NewCounterVec("requests_total", "Total requests.", "method", "path")
+		g := NewGaugeVec("in_flight", "In flight\nrequests.")
+		reg.MustRegister(c, g)
+
+		c.Inc("GET", `/a"b`)
+		c.Add(2, "GET", "/")
+		g.Set(3)
+
+		sb := strings.Builder{}
+		require.NoError(t, reg.WriteText(&sb))
+
+		expected := `# HELP in_flight In flight\nrequests.
+# TYPE in_flight gauge
+in_flight 3
+# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total{method="GET",path="/"} 2
+requests_total{method="GET",path="/a\"b"} 1
+`
+		assert.Equal(t, expected, sb.String())
+	})
+
+	t.Run("should serve text exposition", func(t *testing.T) {
+		reg := NewRegistry()
+		g := NewGaugeVec("up", "")
+		reg.MustRegister(g)
+		g.Set(1)
+
+		rec := httptest.NewRecorder()
+		reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "# TYPE up gauge\nup 1\n", rec.Body.String())
+	})
+}
+
+func TestFormatFloat(t *testing.T) {
+	assert.Equal(t, "+Inf", formatFloat(math.Inf(1)))
+	assert.Equal(t, "-Inf", formatFloat(math.Inf(-1)))
+	assert.Equal(t, "NaN", formatFloat(math.NaN()))
+	assert.Equal(t, "0.25", formatFloat(0.25))
+	assert.Equal(t, "1e+06", formatFloat(1e6))
+}
@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are latency buckets in seconds suited to HTTP handlers.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type series struct {
+	values  []string
+	value   float64
+	buckets []uint64
+	count   uint64
+}
+
+// vec keeps one series per label value combination.
+type vec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newVec(name, help string, labels []string) vec {
+	return vec{name: name, help: help, labels: labels, series: map[string]*series{}}
+}
+
+// with must be called with v.mu held.
+func (v *vec) with(values []string, init func(*series)) *series {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{values: slices.Clone(values)}
+		if init != nil {
+			init(s)
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *vec) Describe() []string {
+	return []string{v.name}
+}
+
+// sorted must be called with v.mu held.
+func (v *vec) sorted() []*series {
+	ss := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		ss = append(ss, s)
+	}
+	slices.SortFunc(ss, func(a, b *series) int {
+		return slices.Compare(a.values, b.values)
+	})
+	return ss
+}
+
+func (v *vec) labelsOf(s *series, extra ...Label) []Label {
+	labels := make([]Label, 0, len(v.labels)+len(extra))
+	for i, name := range v.labels {
+		labels = append(labels, Label{Name: name, Value: s.values[i]})
+	}
+	return append(labels, extra...)
+}
+
+func (v *vec) collect(typ MetricType) []Family {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	f := Family{Name: v.name, Help: v.help, Type: typ}
+	for _, s := range v.sorted() {
+		f.Samples = append(f.Samples, Sample{Labels: v.labelsOf(s), Value: s.value})
+	}
+	return []Family{f}
+}
+
+type CounterVec struct {
+	vec
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{vec: newVec(name, help, labels)}
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increases the counter, negative values are ignored since counters only go up.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.with(values, nil).value += delta
+}
+
+func (c *CounterVec) Collect() []Family {
+	return c.collect(TypeCounter)
+}
+
+type GaugeVec struct {
+	vec
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{vec: newVec(name, help, labels)}
+}
+
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(values, nil).value = value
+}
+
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(values, nil).value += delta
+}
+
+func (g *GaugeVec) Inc(values ...string) {
+	g.Add(1, values...)
+}
+
+func (g *GaugeVec) Dec(values ...string) {
+	g.Add(-1, values...)
+}
+
+func (g *GaugeVec) Collect() []Family {
+	return g.collect(TypeGauge)
+}
+
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+// NewHistogramVec creates a histogram, nil buckets means DefBuckets.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &HistogramVec{vec: newVec(name, help, labels), buckets: buckets}
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.with(values, func(s *series) {
+		s.buckets = make([]uint64, len(h.buckets))
+	})
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.buckets) {
+		s.buckets[i]++
+	}
+	s.count++
+	s.value += value
+}
+
+func (h *HistogramVec) Collect() []Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, s := range h.sorted() {
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.buckets[i]
+			f.Samples = append(f.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: h.labelsOf(s, Label{Name: "le", Value: formatFloat(upper)}),
+				Value:  float64(cumulative),
+			})
+		}
+		f.Samples = append(f.Samples,
+			Sample{Suffix: "_bucket", Labels: h.labelsOf(s, Label{Name: "le", Value: "+Inf"}), Value: float64(s.count)},
+			Sample{Suffix: "_sum", Labels: h.labelsOf(s), Value: s.value},
+			Sample{Suffix: "_count", Labels: h.labelsOf(s), Value: float64(s.count)},
+		)
+	}
+	return []Family{f}
+}
+
+// NewCollectorFunc adapts collect into a Collector for values read at scrape time.
+func NewCollectorFunc(collect func() []Family, names ...string) Collector {
+	return collectorFunc{names: names, collect: collect}
+}
+
+type collectorFunc struct {
+	names   []string
+	collect func() []Family
+}
+
+func (c collectorFunc) Describe() []string { return c.names }
+func (c collectorFunc) Collect() []Family  { return c.collect() }
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterVec(t *testing.T) {
+	t.Run("should ignore negative delta", func(t *testing.T) {
+		c := NewCounterVec("a_total", "", "k")
+		c.Add(2, "v")
+		c.Add(-1, "v")
+
+		f := c.Collect()
+		assert.Equal(t, []Sample{{Labels: []Label{{Name: "k", Value: "v"}}, Value: 2}}, f[0].Samples)
+	})
+
+	t.Run("should panic on label count mismatch", func(t *testing.T) {
+		c := NewCounterVec("a_total", "", "k")
+		assert.Panics(t, func() { c.Inc() })
+	})
+}
+
+func TestGaugeVec(t *testing.T) {
+	g := NewGaugeVec("a", "")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+	g.Add(0.5)
+
+	f := g.Collect()
+	assert.Equal(t, TypeGauge, f[0].Type)
+	assert.Equal(t, 1.5, f[0].Samples[0].Value)
+}
+
+func TestHistogramVec(t *testing.T) {
+	t.Run("should count cumulative buckets", func(t *testing.T) {
+		h := NewHistogramVec("latency_seconds", "", []float64{1, 0.1}, "route")
+		h.Observe(0.05, "/")
+		h.Observe(0.1, "/")
+		h.Observe(0.5, "/")
+		h.Observe(3, "/")
+
+		route := Label{Name: "route", Value: "/"}
+		expected := []Sample{
+			{Suffix: "_bucket", Labels: []Label{route, {Name: "le", Value: "0.1"}}, Value: 2},
+			{Suffix: "_bucket", Labels: []Label{route, {Name: "le", Value: "1"}}, Value: 3},
+			{Suffix: "_bucket", Labels: []Label{route, {Name: "le", Value: "+Inf"}}, Value: 4},
+			{Suffix: "_sum", Labels: []Label{route}, Value: 3.65},
+			{Suffix: "_count", Labels: []Label{route}, Value: 4},
+		}
+		f := h.Collect()
+		assert.Equal(t, TypeHistogram, f[0].Type)
+		assert.Equal(t, expected, f[0].Samples)
+	})
+
+	t.Run("should use default buckets", func(t *testing.T) {
+		h := NewHistogramVec("a", "", nil)
+		h.Observe(1)
+
+		assert.Len(t, h.Collect()[0].Samples, len(DefBuckets)+3)
+	})
+}
@@ -0,0 +1,78 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+type stdoutSpan struct {
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	TraceID       string         `json:"traceId"`
+	SpanID        string         `json:"spanId"`
+	ParentSpanID  string         `json:"parentSpanId,omitempty"`
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	Duration      string         `json:"duration"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Status        string         `json:"status"`
+	StatusMessage string         `json:"statusMessage,omitempty"`
+}
+
+type stdoutExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewStdoutExporter writes one JSON object per span to w, or os.Stdout when w is nil.
+func NewStdoutExporter(w io.Writer) *stdoutExporter {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &stdoutExporter{enc: json.NewEncoder(w)}
+}
+
+func (e *stdoutExporter) Export(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range spans {
+		err := e.enc.Encode(stdoutSpan{
+			Name:          s.Name,
+			Kind:          s.Kind.String(),
+			TraceID:       s.TraceID,
+			SpanID:        s.SpanID,
+			ParentSpanID:  s.ParentSpanID,
+			Start:         s.Start,
+			End:           s.End,
+			Duration:      s.End.Sub(s.Start).String(),
+			Attributes:    attrMap(s.Attributes),
+			Status:        s.Status.String(),
+			StatusMessage: s.StatusMessage,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *stdoutExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func attrMap(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value.Resolve().Any()
+	}
+	return m
+}
@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpanData() SpanData {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return SpanData{
+		Name:          "GET /api/v1/members",
+		Kind:          SpanKindServer,
+		TraceID:       "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:        "00f067aa0ba902b7",
+		ParentSpanID:  "b7ad6b7169203331",
+		Start:         start,
+		End:           start.Add(time.Second),
+		Attributes:    []slog.Attr{slog.String("http.route", "/api/v1/members"), slog.Int("http.response.status_code", 500)},
+		Status:        StatusError,
+		StatusMessage: "boom",
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	t.Run("should write one json line per span", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		e := NewStdoutExporter(buf)
+
+		require.NoError(t, e.Export(t.Context(), []SpanData{newSpanData(), newSpanData()}))
+		require.NoError(t, e.Shutdown(t.Context()))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(lines[0], &got))
+		assert.Equal(t, "server", got["kind"])
+		assert.Equal(t, "error", got["status"])
+		assert.Equal(t, "1s", got["duration"])
+		assert.Equal(t, map[string]any{"http.route": "/api/v1/members", "http.response.status_code": float64(500)}, got["attributes"])
+	})
+}
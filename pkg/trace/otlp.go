@@ -0,0 +1,178 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	otlpTracesPath     = "/v1/traces"
+	otlpDefaultTimeout = 10 * time.Second
+	scopeName          = "github.com/kongsakchai/gotemplate/pkg/trace"
+)
+
+type otlpExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter sends spans as OTLP/HTTP JSON to endpoint + /v1/traces.
+func NewOTLPExporter(endpoint, serviceName string, timeout time.Duration) *otlpExporter {
+	if timeout <= 0 {
+		timeout = otlpDefaultTimeout
+	}
+	return &otlpExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func (e *otlpExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("otlp export: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *otlpExporter) Shutdown(_ context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+func (e *otlpExporter) request(spans []SpanData) otlpRequest {
+	scope := otlpScopeSpans{
+		Scope: otlpScope{Name: scopeName},
+		Spans: make([]otlpSpan, 0, len(spans)),
+	}
+
+	for _, s := range spans {
+		status := otlpStatus{Code: int(s.Status)}
+		if s.Status == StatusError {
+			status.Message = s.StatusMessage
+		}
+		scope.Spans = append(scope.Spans, otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              int(s.Kind),
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            status,
+		})
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource:   otlpResource{Attributes: otlpAttributes([]slog.Attr{slog.String("service.name", e.serviceName)})},
+			ScopeSpans: []otlpScopeSpans{scope},
+		}},
+	}
+}
+
+func otlpAttributes(attrs []slog.Attr) []otlpAttribute {
+	out := make([]otlpAttribute, 0, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		var value otlpValue
+		switch v.Kind() {
+		case slog.KindInt64:
+			s := strconv.FormatInt(v.Int64(), 10)
+			value.IntValue = &s
+		case slog.KindUint64:
+			s := strconv.FormatUint(v.Uint64(), 10)
+			value.IntValue = &s
+		case slog.KindFloat64:
+			f := v.Float64()
+			value.DoubleValue = &f
+		case slog.KindBool:
+			b := v.Bool()
+			value.BoolValue = &b
+		default:
+			s := v.String()
+			value.StringValue = &s
+		}
+		out = append(out, otlpAttribute{Key: a.Key, Value: value})
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	t.Run("should post spans to collector", func(t *testing.T) {
+		var got otlpRequest
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/traces", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			b, _ := io.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &got))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer collector.Close()
+
+		e := NewOTLPExporter(collector.URL+"/", "gotemplate", time.Second)
+		require.NoError(t, e.Export(t.Context(), []SpanData{newSpanData()}))
+		require.NoError(t, e.Shutdown(t.Context()))
+
+		require.Len(t, got.ResourceSpans, 1)
+		rs := got.ResourceSpans[0]
+		assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+		assert.Equal(t, "gotemplate", *rs.Resource.Attributes[0].Value.StringValue)
+
+		span := rs.ScopeSpans[0].Spans[0]
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+		assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID)
+		assert.Equal(t, 2, span.Kind)
+		assert.Equal(t, "1735689600000000000", span.StartTimeUnixNano)
+		assert.Equal(t, otlpStatus{Code: 2, Message: "boom"}, span.Status)
+		assert.Equal(t, "500", *span.Attributes[1].Value.IntValue)
+	})
+
+	t.Run("should return error when collector reject", func(t *testing.T) {
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer collector.Close()
+
+		e := NewOTLPExporter(collector.URL, "gotemplate", 0)
+		assert.Error(t, e.Export(t.Context(), []SpanData{newSpanData()}))
+	})
+
+	t.Run("should return error when collector unreachable", func(t *testing.T) {
+		e := NewOTLPExporter("http://localhost:1", "gotemplate", time.Second)
+		assert.Error(t, e.Export(t.Context(), []SpanData{newSpanData()}))
+	})
+}
+
+func TestOTLPAttributes(t *testing.T) {
+	t.Run("should map value kinds", func(t *testing.T) {
+		attrs := otlpAttributes([]slog.Attr{
+			slog.String("s", "v"),
+			slog.Int64("i", 1),
+			slog.Uint64("u", 2),
+			slog.Float64("f", 1.5),
+			slog.Bool("b", true),
+			slog.Duration("d", time.Second),
+		})
+
+		assert.Equal(t, "v", *attrs[0].Value.StringValue)
+		assert.Equal(t, "1", *attrs[1].Value.IntValue)
+		assert.Equal(t, "2", *attrs[2].Value.IntValue)
+		assert.Equal(t, 1.5, *attrs[3].Value.DoubleValue)
+		assert.True(t, *attrs[4].Value.BoolValue)
+		assert.Equal(t, "1s", *attrs[5].Value.StringValue)
+	})
+}
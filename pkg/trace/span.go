@@ -0,0 +1,124 @@
+package trace
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota + 1
+	SpanKindServer
+	SpanKindClient
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+func (c StatusCode) String() string {
+	switch c {
+	case StatusOK:
+		return "ok"
+	case StatusError:
+		return "error"
+	default:
+		return "unset"
+	}
+}
+
+// SpanData is the immutable snapshot of an ended span handed to exporters.
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Start         time.Time
+	End           time.Time
+	Attributes    []slog.Attr
+	Status        StatusCode
+	StatusMessage string
+}
+
+type Span struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	data   SpanData
+	sc     SpanContext
+	ended  bool
+}
+
+func (s *Span) SpanContext() SpanContext {
+	return s.sc
+}
+
+func (s *Span) SetAttributes(attrs ...slog.Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = code
+	s.data.StatusMessage = msg
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetAttributes(slog.String("error", err.Error()))
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End records the end time and queues the span for export. Calls after the first are ignored.
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.tracer.enqueue(data)
+	}
+}
+
+type SpanOption func(*SpanData)
+
+func WithKind(kind SpanKind) SpanOption {
+	return func(d *SpanData) {
+		d.Kind = kind
+	}
+}
+
+func WithAttributes(attrs ...slog.Attr) SpanOption {
+	return func(d *SpanData) {
+		d.Attributes = append(d.Attributes, attrs...)
+	}
+}
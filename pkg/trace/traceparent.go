@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+
+	flagSampled    = 0x01
+	maxStateLength = 512
+)
+
+var (
+	zeroTraceID = strings.Repeat("0", 32)
+	zeroSpanID  = strings.Repeat("0", 16)
+)
+
+type contextKey struct{}
+
+// SpanContext is the W3C Trace Context identity of a span.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+	State   string
+}
+
+func (sc SpanContext) IsValid() bool {
+	return isHex(sc.TraceID, 32) && sc.TraceID != zeroTraceID && isHex(sc.SpanID, 16) && sc.SpanID != zeroSpanID
+}
+
+// TraceParent formats the span context as a version 00 traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	var flags byte
+	if sc.Sampled {
+		flags |= flagSampled
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, flags)
+}
+
+// Child returns a span context in the same trace with a new span ID.
+func (sc SpanContext) Child() SpanContext {
+	sc.SpanID = newID(8)
+	return sc
+}
+
+func NewSpanContext() SpanContext {
+	return SpanContext{
+		TraceID: newID(16),
+		SpanID:  newID(8),
+		Sampled: true,
+	}
+}
+
+// ParseTraceParent parses a traceparent header value.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+func ParseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || !isHex(flags, 2) {
+		return SpanContext{}, false
+	}
+	if version == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	b, _ := hex.DecodeString(flags)
+	sc := SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: b[0]&flagSampled != 0,
+	}
+	return sc, sc.IsValid()
+}
+
+// Extract reads traceparent and tracestate from the headers.
+func Extract(h http.Header) (SpanContext, bool) {
+	sc, ok := ParseTraceParent(h.Get(HeaderTraceParent))
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	if state := strings.Join(h.Values(HeaderTraceState), ","); len(state) <= maxStateLength {
+		sc.State = state
+	}
+	return sc, true
+}
+
+// Inject writes traceparent and tracestate to the headers.
+func Inject(h http.Header, sc SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+
+	h.Set(HeaderTraceParent, sc.TraceParent())
+	if sc.State != "" {
+		h.Set(HeaderTraceState, sc.State)
+	}
+}
+
+func ContextWith(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+	return sc, ok
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string, size int) bool {
+	if len(s) != size {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	testcases := []struct {
+		title string
+		value string
+		want  SpanContext
+		ok    bool
+	}{
+		{
+			title: "should parse sampled traceparent",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:  SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+			ok:    true,
+		},
+		{
+			title: "should parse not sampled traceparent",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			want:  SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"},
+			ok:    true,
+		},
+		{
+			title: "should accept future version with extra fields",
+			value: "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			want:  SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+			ok:    true,
+		},
+		{title: "should reject empty", value: ""},
+		{title: "should reject version ff", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{title: "should reject extra fields in version 00", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-x"},
+		{title: "should reject upper case", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{title: "should reject zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{title: "should reject zero span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{title: "should reject short span id", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.title, func(t *testing.T) {
+			sc, ok := ParseTraceParent(tc.value)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.want, sc)
+			}
+		})
+	}
+}
+
+func TestSpanContext(t *testing.T) {
+	t.Run("should create valid root span context", func(t *testing.T) {
+		sc := NewSpanContext()
+		assert.True(t, sc.IsValid())
+		assert.True(t, sc.Sampled)
+	})
+
+	t.Run("should keep trace id for child", func(t *testing.T) {
+		sc := NewSpanContext()
+		sc.State = "vendor=value"
+		child := sc.Child()
+
+		assert.Equal(t, sc.TraceID, child.TraceID)
+		assert.Equal(t, sc.State, child.State)
+		assert.NotEqual(t, sc.SpanID, child.SpanID)
+	})
+
+	t.Run("should format traceparent", func(t *testing.T) {
+		sc := SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.TraceParent())
+
+		sc.Sampled = false
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", sc.TraceParent())
+	})
+}
+
+func TestExtractInject(t *testing.T) {
+	t.Run("should round trip headers", func(t *testing.T) {
+		sc := NewSpanContext()
+		sc.State = "vendor=value"
+
+		h := http.Header{}
+		Inject(h, sc)
+
+		got, ok := Extract(h)
+		assert.True(t, ok)
+		assert.Equal(t, sc, got)
+	})
+
+	t.Run("should not inject invalid span context", func(t *testing.T) {
+		h := http.Header{}
+		Inject(h, SpanContext{})
+		assert.Empty(t, h.Get(HeaderTraceParent))
+	})
+
+	t.Run("should return false when header missing", func(t *testing.T) {
+		_, ok := Extract(http.Header{})
+		assert.False(t, ok)
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Run("should store span context in context", func(t *testing.T) {
+		sc := NewSpanContext()
+		got, ok := FromContext(ContextWith(context.Background(), sc))
+		assert.True(t, ok)
+		assert.Equal(t, sc, got)
+	})
+
+	t.Run("should return false when not set", func(t *testing.T) {
+		_, ok := FromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,179 @@
+package trace
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	queueSize     = 2048
+	batchSize     = 512
+	flushInterval = 5 * time.Second
+)
+
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+type Config struct {
+	ServiceName string
+	Exporter    string // stdout|otlp
+	Endpoint    string
+	Timeout     time.Duration
+}
+
+type Tracer struct {
+	exporter Exporter
+	queue    chan SpanData
+	done     chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// noopTracer creates spans for propagation only, nothing is exported.
+var noopTracer = &Tracer{}
+
+// New creates a tracer with the exporter selected by cfg.Exporter.
+func New(cfg Config) *Tracer {
+	var exporter Exporter
+	switch cfg.Exporter {
+	case "otlp":
+		exporter = NewOTLPExporter(cfg.Endpoint, cfg.ServiceName, cfg.Timeout)
+	default:
+		exporter = NewStdoutExporter(nil)
+	}
+	return NewTracer(exporter)
+}
+
+func NewTracer(exporter Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		queue:    make(chan SpanData, queueSize),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+func Default() *Tracer {
+	if t := defaultTracer.Load(); t != nil {
+		return t
+	}
+	return noopTracer
+}
+
+// Start starts a span with the default tracer. See [Tracer.Start].
+func Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return Default().Start(ctx, name, opts...)
+}
+
+// Start creates a child of the span context in ctx, or a new trace when there is none.
+// The returned context carries the new span context.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	sc := NewSpanContext()
+	parentID := ""
+	if parent, ok := FromContext(ctx); ok {
+		sc = parent.Child()
+		parentID = parent.SpanID
+	}
+
+	data := SpanData{
+		Name:         name,
+		Kind:         SpanKindInternal,
+		TraceID:      sc.TraceID,
+		SpanID:       sc.SpanID,
+		ParentSpanID: parentID,
+		Start:        time.Now(),
+	}
+	for _, opt := range opts {
+		opt(&data)
+	}
+
+	return ContextWith(ctx, sc), &Span{tracer: t, data: data, sc: sc}
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	if t.queue == nil {
+		return
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+
+	select {
+	case t.queue <- data:
+	default:
+		slog.Warn("trace queue is full, span dropped", "span", data.Name)
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.exporter.Export(context.Background(), batch); err != nil {
+			slog.Error("export spans fail", "err", err.Error(), "count", len(batch))
+		}
+		batch = make([]SpanData, 0, batchSize)
+	}
+
+	for {
+		select {
+		case data, ok := <-t.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, data)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Shutdown exports the queued spans and shuts the exporter down.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.queue == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.queue)
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.exporter.Shutdown(ctx)
+}
@@ -0,0 +1,139 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryExporter struct {
+	mu       sync.Mutex
+	spans    []SpanData
+	shutdown bool
+}
+
+func (e *memoryExporter) Export(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(_ context.Context) error {
+	e.shutdown = true
+	return nil
+}
+
+func TestTracerStart(t *testing.T) {
+	t.Run("should start root span when no parent", func(t *testing.T) {
+		tracer := NewTracer(&memoryExporter{})
+		ctx, span := tracer.Start(context.Background(), "root")
+
+		sc, ok := FromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, span.SpanContext(), sc)
+		assert.True(t, sc.IsValid())
+		assert.Empty(t, span.data.ParentSpanID)
+	})
+
+	t.Run("should start child span of parent", func(t *testing.T) {
+		tracer := NewTracer(&memoryExporter{})
+		parent := NewSpanContext()
+		_, span := tracer.Start(ContextWith(context.Background(), parent), "child", WithKind(SpanKindClient))
+
+		assert.Equal(t, parent.TraceID, span.SpanContext().TraceID)
+		assert.Equal(t, parent.SpanID, span.data.ParentSpanID)
+		assert.Equal(t, SpanKindClient, span.data.Kind)
+	})
+}
+
+func TestTracerExport(t *testing.T) {
+	t.Run("should export ended spans on shutdown", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter)
+
+		_, span := tracer.Start(context.Background(), "work", WithAttributes(slog.String("key", "value")))
+		span.SetAttributes(slog.Int("count", 1))
+		span.RecordError(errors.New("boom"))
+		span.End()
+		span.End()
+
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		require.Len(t, exporter.spans, 1)
+
+		got := exporter.spans[0]
+		assert.Equal(t, "work", got.Name)
+		assert.Equal(t, StatusError, got.Status)
+		assert.Equal(t, "boom", got.StatusMessage)
+		assert.Contains(t, got.Attributes, slog.String("key", "value"))
+		assert.Contains(t, got.Attributes, slog.Int("count", 1))
+		assert.False(t, got.End.Before(got.Start))
+		assert.True(t, exporter.shutdown)
+	})
+
+	t.Run("should not export not sampled span", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter)
+
+		parent := NewSpanContext()
+		parent.Sampled = false
+		_, span := tracer.Start(ContextWith(context.Background(), parent), "work")
+		span.End()
+
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		assert.Empty(t, exporter.spans)
+	})
+
+	t.Run("should ignore span ended after shutdown", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter)
+		_, span := tracer.Start(context.Background(), "late")
+
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		span.End()
+		assert.Empty(t, exporter.spans)
+	})
+
+	t.Run("should ignore nil error", func(t *testing.T) {
+		_, span := NewTracer(&memoryExporter{}).Start(context.Background(), "ok")
+		span.RecordError(nil)
+		assert.Equal(t, StatusUnset, span.data.Status)
+	})
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("should use noop tracer by default", func(t *testing.T) {
+		ctx, span := Start(context.Background(), "noop")
+		span.End()
+
+		_, ok := FromContext(ctx)
+		assert.True(t, ok)
+		assert.NoError(t, Default().Shutdown(context.Background()))
+	})
+
+	t.Run("should use tracer set as default", func(t *testing.T) {
+		defer SetDefault(nil)
+
+		tracer := NewTracer(&memoryExporter{})
+		SetDefault(tracer)
+		assert.Same(t, tracer, Default())
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should create stdout tracer", func(t *testing.T) {
+		tracer := New(Config{Exporter: "stdout"})
+		assert.IsType(t, &stdoutExporter{}, tracer.exporter)
+	})
+
+	t.Run("should create otlp tracer", func(t *testing.T) {
+		tracer := New(Config{Exporter: "otlp", Endpoint: "http://localhost:4318"})
+		assert.IsType(t, &otlpExporter{}, tracer.exporter)
+	})
+}
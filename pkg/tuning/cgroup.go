@@ -0,0 +1,119 @@
+package tuning
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// v1 reports "no limit" as a page-aligned max int64 rather than -1.
+const unlimitedV1Memory = math.MaxInt64 &^ 4095
+
+const cgroupRoot = "sys/fs/cgroup"
+
+// Limits of the container, zero means unlimited.
+type Limits struct {
+	Version int
+	CPU     float64 // cores
+	Memory  int64   // bytes
+}
+
+// ReadLimits reads cgroup v2 or v1 limits from fsys rooted at "/".
+func ReadLimits(fsys fs.FS) (Limits, error) {
+	if _, err := fs.Stat(fsys, path.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		return readV2(fsys, path.Join(cgroupRoot, v2Path(fsys)))
+	}
+	if _, err := fs.Stat(fsys, path.Join(cgroupRoot, "memory")); err == nil {
+		return readV1(fsys)
+	}
+	if _, err := fs.Stat(fsys, path.Join(cgroupRoot, "cpu")); err == nil {
+		return readV1(fsys)
+	}
+	return Limits{}, errors.New("cgroup not found")
+}
+
+// v2Path returns the cgroup of this process from "0::/path" in /proc/self/cgroup.
+func v2Path(fsys fs.FS) string {
+	f, err := fsys.Open("proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if p, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			p = strings.TrimPrefix(p, "/")
+			if _, err := fs.Stat(fsys, path.Join(cgroupRoot, p, "cpu.max")); err == nil {
+				return p
+			}
+			if _, err := fs.Stat(fsys, path.Join(cgroupRoot, p, "memory.max")); err == nil {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+func readV2(fsys fs.FS, dir string) (Limits, error) {
+	limits := Limits{Version: 2}
+
+	if s, err := readFile(fsys, path.Join(dir, "cpu.max")); err == nil {
+		quota, period, _ := strings.Cut(s, " ")
+		if quota != "max" {
+			q, err := strconv.ParseFloat(quota, 64)
+			if err != nil {
+				return limits, err
+			}
+			p, err := strconv.ParseFloat(period, 64)
+			if err != nil || p <= 0 {
+				p = 100000
+			}
+			limits.CPU = q / p
+		}
+	}
+
+	if s, err := readFile(fsys, path.Join(dir, "memory.max")); err == nil && s != "max" {
+		m, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return limits, err
+		}
+		limits.Memory = m
+	}
+
+	return limits, nil
+}
+
+func readV1(fsys fs.FS) (Limits, error) {
+	limits := Limits{Version: 1}
+
+	quota, qerr := readInt(fsys, path.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	period, perr := readInt(fsys, path.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if qerr == nil && perr == nil && quota > 0 && period > 0 {
+		limits.CPU = float64(quota) / float64(period)
+	}
+
+	memory, err := readInt(fsys, path.Join(cgroupRoot, "memory", "memory.limit_in_bytes"))
+	if err == nil && memory > 0 && memory < unlimitedV1Memory {
+		limits.Memory = memory
+	}
+
+	return limits, nil
+}
+
+func readFile(fsys fs.FS, name string) (string, error) {
+	b, err := fs.ReadFile(fsys, name)
+	return strings.TrimSpace(string(b)), err
+}
+
+func readInt(fsys fs.FS, name string) (int64, error) {
+	s, err := readFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
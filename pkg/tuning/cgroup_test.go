@@ -0,0 +1,91 @@
+package tuning
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func file(s string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(s)}
+}
+
+func TestReadLimits(t *testing.T) {
+	t.Run("should read cgroup v2 limits", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cgroup.controllers": file("cpu memory"),
+			"sys/fs/cgroup/cpu.max":            file("150000 100000\n"),
+			"sys/fs/cgroup/memory.max":         file("536870912\n"),
+		}
+
+		limits, err := ReadLimits(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, Limits{Version: 2, CPU: 1.5, Memory: 512 << 20}, limits)
+	})
+
+	t.Run("should read cgroup v2 unlimited", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cgroup.controllers": file("cpu memory"),
+			"sys/fs/cgroup/cpu.max":            file("max 100000"),
+			"sys/fs/cgroup/memory.max":         file("max"),
+		}
+
+		limits, err := ReadLimits(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, Limits{Version: 2}, limits)
+	})
+
+	t.Run("should read cgroup v2 limits of process cgroup", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup":                               file("0::/system.slice/app.service\n"),
+			"sys/fs/cgroup/cgroup.controllers":               file("cpu memory"),
+			"sys/fs/cgroup/cpu.max":                          file("max 100000"),
+			"sys/fs/cgroup/system.slice/app.service/cpu.max": file("200000 100000"),
+		}
+
+		limits, err := ReadLimits(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, limits.CPU)
+	})
+
+	t.Run("should read cgroup v1 limits", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         file("250000"),
+			"sys/fs/cgroup/cpu/cpu.cfs_period_us":        file("100000"),
+			"sys/fs/cgroup/memory/memory.limit_in_bytes": file("1073741824"),
+		}
+
+		limits, err := ReadLimits(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, Limits{Version: 1, CPU: 2.5, Memory: 1 << 30}, limits)
+	})
+
+	t.Run("should read cgroup v1 unlimited", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         file("-1"),
+			"sys/fs/cgroup/cpu/cpu.cfs_period_us":        file("100000"),
+			"sys/fs/cgroup/memory/memory.limit_in_bytes": file("9223372036854771712"),
+		}
+
+		limits, err := ReadLimits(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, Limits{Version: 1}, limits)
+	})
+
+	t.Run("should fail without cgroup", func(t *testing.T) {
+		_, err := ReadLimits(fstest.MapFS{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on malformed file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cgroup.controllers": file("cpu memory"),
+			"sys/fs/cgroup/memory.max":         file("lots"),
+		}
+
+		_, err := ReadLimits(fsys)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,84 @@
+package tuning
+
+import (
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+const defaultMemoryRatio = 0.9
+
+type Config struct {
+	// MemoryRatio is the fraction of the container memory limit used for GOMEMLIMIT.
+	MemoryRatio float64
+}
+
+// Result describes what Apply decided, zero values mean the runtime default was kept.
+type Result struct {
+	MaxProcs    int
+	MemoryLimit int64
+}
+
+type tuner struct {
+	fsys           fs.FS
+	getenv         func(string) string
+	setMaxProcs    func(int) int
+	setMemoryLimit func(int64) int64
+	logger         *slog.Logger
+}
+
+// Apply sets GOMAXPROCS to the rounded cgroup CPU quota and GOMEMLIMIT to MemoryRatio of the
+// cgroup memory limit. GOMAXPROCS and GOMEMLIMIT env vars always win since the runtime already applied them.
+func Apply(cfg Config, logger *slog.Logger) Result {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return tuner{
+		fsys:           os.DirFS("/"),
+		getenv:         os.Getenv,
+		setMaxProcs:    runtime.GOMAXPROCS,
+		setMemoryLimit: debug.SetMemoryLimit,
+		logger:         logger,
+	}.apply(cfg)
+}
+
+func (t tuner) apply(cfg Config) Result {
+	if cfg.MemoryRatio <= 0 || cfg.MemoryRatio > 1 {
+		cfg.MemoryRatio = defaultMemoryRatio
+	}
+
+	limits, err := ReadLimits(t.fsys)
+	if err != nil {
+		t.logger.Info("runtime tuning skipped", "reason", err.Error())
+	}
+
+	var result Result
+	switch {
+	case t.getenv("GOMAXPROCS") != "":
+		t.logger.Info("GOMAXPROCS set by env", "value", t.getenv("GOMAXPROCS"))
+	case limits.CPU > 0:
+		procs := max(1, int(math.Round(limits.CPU)))
+		t.setMaxProcs(procs)
+		result.MaxProcs = procs
+		t.logger.Info("GOMAXPROCS set from cgroup cpu quota", "value", procs, "quota", limits.CPU, "cgroup", limits.Version)
+	default:
+		t.logger.Info("GOMAXPROCS left at runtime default", "value", t.setMaxProcs(0))
+	}
+
+	switch {
+	case t.getenv("GOMEMLIMIT") != "":
+		t.logger.Info("GOMEMLIMIT set by env", "value", t.getenv("GOMEMLIMIT"))
+	case limits.Memory > 0:
+		limit := int64(float64(limits.Memory) * cfg.MemoryRatio)
+		t.setMemoryLimit(limit)
+		result.MemoryLimit = limit
+		t.logger.Info("GOMEMLIMIT set from cgroup memory limit", "value", limit, "limit", limits.Memory, "ratio", cfg.MemoryRatio, "cgroup", limits.Version)
+	default:
+		t.logger.Info("GOMEMLIMIT left unlimited")
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package tuning
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRuntime struct {
+	procs int
+	limit int64
+}
+
+func newTuner(fsys fstest.MapFS, env map[string]string, rt *fakeRuntime) tuner {
+	return tuner{
+		fsys:   fsys,
+		getenv: func(key string) string { return env[key] },
+		setMaxProcs: func(n int) int {
+			prev := rt.procs
+			if n > 0 {
+				rt.procs = n
+			}
+			return prev
+		},
+		setMemoryLimit: func(n int64) int64 {
+			prev := rt.limit
+			if n >= 0 {
+				rt.limit = n
+			}
+			return prev
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestApply(t *testing.T) {
+	container := fstest.MapFS{
+		"sys/fs/cgroup/cgroup.controllers": file("cpu memory"),
+		"sys/fs/cgroup/cpu.max":            file("250000 100000"),
+		"sys/fs/cgroup/memory.max":         file("1000000000"),
+	}
+
+	t.Run("should set rounded quota and memory ratio", func(t *testing.T) {
+		rt := &fakeRuntime{procs: 64}
+
+		result := newTuner(container, nil, rt).apply(Config{MemoryRatio: 0.8})
+
+		assert.Equal(t, Result{MaxProcs: 3, MemoryLimit: 800000000}, result)
+		assert.Equal(t, 3, rt.procs)
+		assert.Equal(t, int64(800000000), rt.limit)
+	})
+
+	t.Run("should use default ratio", func(t *testing.T) {
+		rt := &fakeRuntime{}
+
+		result := newTuner(container, nil, rt).apply(Config{})
+
+		assert.Equal(t, int64(900000000), result.MemoryLimit)
+	})
+
+	t.Run("should keep at least one proc", func(t *testing.T) {
+		rt := &fakeRuntime{procs: 64}
+		fsys := fstest.MapFS{
+			"sys/fs/cgroup/cgroup.controllers": file("cpu"),
+			"sys/fs/cgroup/cpu.max":            file("20000 100000"),
+		}
+
+		result := newTuner(fsys, nil, rt).apply(Config{})
+
+		assert.Equal(t, 1, result.MaxProcs)
+		assert.Equal(t, int64(0), result.MemoryLimit)
+	})
+
+	t.Run("should respect env overrides", func(t *testing.T) {
+		rt := &fakeRuntime{procs: 8, limit: 123}
+		env := map[string]string{"GOMAXPROCS": "8", "GOMEMLIMIT": "1GiB"}
+
+		result := newTuner(container, env, rt).apply(Config{})
+
+		assert.Equal(t, Result{}, result)
+		assert.Equal(t, 8, rt.procs)
+		assert.Equal(t, int64(123), rt.limit)
+	})
+
+	t.Run("should keep runtime defaults outside container", func(t *testing.T) {
+		rt := &fakeRuntime{procs: 16}
+
+		result := newTuner(fstest.MapFS{}, nil, rt).apply(Config{})
+
+		assert.Equal(t, Result{}, result)
+		assert.Equal(t, 16, rt.procs)
+	})
+}